@@ -0,0 +1,48 @@
+// Package dispatch sends an HL7 message to a destination URI, shared
+// by anything that needs to forward or replay a message to a
+// downstream system (the hl7d forwarder middleware, the store's replay
+// API, ...) so the scheme-handling logic lives in exactly one place.
+package dispatch
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+	"github.com/sodrety/hl7-middleware/mllp"
+)
+
+// Send delivers msg to dest, which is either "http://host/path",
+// "https://host/path", or "mllp://host:port".
+func Send(dest string, msg *hl7.HL7Message) error {
+	switch {
+	case strings.HasPrefix(dest, "mllp://"):
+		addr := strings.TrimPrefix(dest, "mllp://")
+		client, err := mllp.Dial(addr, 10*time.Second)
+		if err != nil {
+			return fmt.Errorf("dispatch: dial %s: %w", dest, err)
+		}
+		defer client.Close()
+		if _, err := client.Send(msg); err != nil {
+			return fmt.Errorf("dispatch: send to %s: %w", dest, err)
+		}
+		return nil
+
+	case strings.HasPrefix(dest, "http://"), strings.HasPrefix(dest, "https://"):
+		resp, err := http.Post(dest, "x-application/hl7-v2+er7", bytes.NewBufferString(msg.GenerateMessage()))
+		if err != nil {
+			return fmt.Errorf("dispatch: post to %s: %w", dest, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("dispatch: post to %s: status %d", dest, resp.StatusCode)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("dispatch: unsupported destination scheme: %s", dest)
+	}
+}
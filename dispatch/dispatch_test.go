@@ -0,0 +1,18 @@
+package dispatch
+
+import (
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+func TestSendRejectsUnsupportedScheme(t *testing.T) {
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|A|B\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	if err := Send("ftp://example.com", msg); err == nil {
+		t.Fatalf("expected an error for an unsupported destination scheme")
+	}
+}
@@ -0,0 +1,244 @@
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+	"github.com/sodrety/hl7-middleware/terminology"
+)
+
+// Terminology resolves HL7 v2 coded values to FHIR codes during
+// conversion. Assign a custom terminology.Mapper here to override the
+// built-in table 0001/0002 mappings.
+var Terminology terminology.Mapper = terminology.Default()
+
+// ToFHIRBundle converts a parsed HL7 v2 message into a FHIR R4 Bundle
+// of type "message", containing a MessageHeader plus whichever of
+// Patient, Encounter, Observation, and DiagnosticReport the message has
+// segments for.
+func ToFHIRBundle(msg *hl7.HL7Message) (*Bundle, error) {
+	bundle := &Bundle{ResourceType: "Bundle", Type: "message"}
+
+	msh, ok := msg.MSH()
+	if !ok {
+		return nil, fmt.Errorf("fhir: message has no MSH segment")
+	}
+	bundle.Entry = append(bundle.Entry, BundleEntry{Resource: &MessageHeader{
+		ResourceType: "MessageHeader",
+		EventCoding: Coding{
+			System:  "http://terminology.hl7.org/CodeSystem/v2-0003",
+			Code:    msh.TriggerEvent(),
+			Display: msh.MessageType(),
+		},
+		Source: MessageSource{Name: msh.SendingApplication()},
+	}})
+
+	var patientRef *Reference
+	if pid, ok := msg.PID(); ok {
+		patient := &Patient{
+			ResourceType: "Patient",
+			ID:           pid.PatientID(),
+			Gender:       Terminology.AdministrativeGender(pid.Sex()),
+			BirthDate:    toFHIRDate(pid.DateOfBirth()),
+		}
+		if patient.ID != "" {
+			patient.Identifier = []Identifier{{Value: patient.ID}}
+		}
+		if name := (HumanName{Family: pid.FamilyName()}); name.Family != "" {
+			if given := pid.GivenName(); given != "" {
+				name.Given = []string{given}
+			}
+			patient.Name = []HumanName{name}
+		}
+		if ms := pid.MaritalStatus(); ms != "" {
+			patient.MaritalStatus = &CodeableConcept{Coding: []Coding{{Code: Terminology.MaritalStatus(ms)}}}
+		}
+		bundle.Entry = append(bundle.Entry, BundleEntry{Resource: patient})
+		if patient.ID != "" {
+			patientRef = &Reference{Reference: "Patient/" + patient.ID}
+		}
+	}
+
+	if pv1, ok := msg.PV1(); ok {
+		bundle.Entry = append(bundle.Entry, BundleEntry{Resource: &Encounter{
+			ResourceType: "Encounter",
+			ID:           pv1.VisitNumber(),
+			Status:       "in-progress",
+			Class:        Coding{System: "http://terminology.hl7.org/CodeSystem/v3-ActCode", Code: pv1.PatientClass()},
+			Subject:      patientRef,
+		}})
+	}
+
+	if obr, ok := msg.OBR(); ok {
+		bundle.Entry = append(bundle.Entry, BundleEntry{Resource: &DiagnosticReport{
+			ResourceType: "DiagnosticReport",
+			ID:           obr.FillerOrderNumber(),
+			Status:       "final",
+			Code:         CodeableConcept{Text: obr.UniversalServiceID()},
+			Subject:      patientRef,
+		}})
+	}
+
+	for i, obx := range msg.OBXSegments() {
+		bundle.Entry = append(bundle.Entry, BundleEntry{Resource: &Observation{
+			ResourceType: "Observation",
+			ID:           fmt.Sprintf("obx-%d", i+1),
+			Status:       "final",
+			Code:         CodeableConcept{Text: obx.ObservationIdentifier()},
+			Subject:      patientRef,
+			ValueString:  obx.ObservationValue(),
+		}})
+	}
+
+	return bundle, nil
+}
+
+// FromFHIRBundle converts a FHIR R4 message Bundle back into an HL7 v2
+// pipe-delimited message, reversing ToFHIRBundle. bundle.Entry[].Resource
+// may hold either the concrete resource types ToFHIRBundle produces
+// (e.g. *Patient) or map[string]interface{}, as it would after a JSON
+// round trip, so callers can pass a Bundle straight through from
+// ToFHIRBundle as well as one decoded from the wire.
+func FromFHIRBundle(bundle *Bundle) (*hl7.HL7Message, error) {
+	msg := hl7.NewHL7Message()
+
+	var header map[string]interface{}
+	var patient map[string]interface{}
+	var encounter map[string]interface{}
+	var report map[string]interface{}
+	var observations []map[string]interface{}
+
+	for _, entry := range bundle.Entry {
+		res := asResourceMap(entry.Resource)
+		if res == nil {
+			continue
+		}
+		switch res["resourceType"] {
+		case "MessageHeader":
+			header = res
+		case "Patient":
+			patient = res
+		case "Encounter":
+			encounter = res
+		case "DiagnosticReport":
+			report = res
+		case "Observation":
+			observations = append(observations, res)
+		}
+	}
+
+	if header == nil {
+		return nil, fmt.Errorf("fhir: bundle has no MessageHeader resource")
+	}
+
+	sendingApp := ""
+	if source, ok := header["source"].(map[string]interface{}); ok {
+		sendingApp, _ = source["name"].(string)
+	}
+	eventCode, messageType := "", "ADT"
+	if ec, ok := header["eventCoding"].(map[string]interface{}); ok {
+		eventCode, _ = ec["code"].(string)
+		if display, ok := ec["display"].(string); ok && display != "" {
+			messageType = display
+		}
+	}
+	msg.AddSegment("MSH", "^~\\&", sendingApp, "", "", "", "", "", messageType+"^"+eventCode, "", "P", "2.5")
+
+	if patient != nil {
+		id, _ := patient["id"].(string)
+		gender, _ := patient["gender"].(string)
+		birthDate, _ := patient["birthDate"].(string)
+		family, given := "", ""
+		if names, ok := patient["name"].([]interface{}); ok && len(names) > 0 {
+			if n, ok := names[0].(map[string]interface{}); ok {
+				family, _ = n["family"].(string)
+				if givenList, ok := n["given"].([]interface{}); ok && len(givenList) > 0 {
+					given, _ = givenList[0].(string)
+				}
+			}
+		}
+		msg.AddSegment("PID", "", id, "", "", family+"^"+given, "", fromFHIRDate(birthDate), v2Sex(gender))
+	}
+
+	if encounter != nil {
+		class := ""
+		if c, ok := encounter["class"].(map[string]interface{}); ok {
+			class, _ = c["code"].(string)
+		}
+		msg.AddSegment("PV1", "", class)
+	}
+
+	if report != nil {
+		serviceID := ""
+		if code, ok := report["code"].(map[string]interface{}); ok {
+			serviceID, _ = code["text"].(string)
+		}
+		msg.AddSegment("OBR", "", "", "", serviceID)
+	}
+
+	for _, obs := range observations {
+		identifier, value := "", ""
+		if code, ok := obs["code"].(map[string]interface{}); ok {
+			identifier, _ = code["text"].(string)
+		}
+		value, _ = obs["valueString"].(string)
+		msg.AddSegment("OBX", "", "ST", identifier, "", value)
+	}
+
+	return msg, nil
+}
+
+// asResourceMap normalizes a BundleEntry.Resource to a
+// map[string]interface{} regardless of whether it holds one of this
+// package's concrete resource types (as produced by ToFHIRBundle) or a
+// generic map (as produced by decoding a Bundle from JSON). Returns nil
+// if resource is neither.
+func asResourceMap(resource interface{}) map[string]interface{} {
+	if res, ok := resource.(map[string]interface{}); ok {
+		return res
+	}
+	if resource == nil {
+		return nil
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil
+	}
+	return res
+}
+
+// toFHIRDate converts an HL7 DT value (YYYYMMDD) to a FHIR date
+// (YYYY-MM-DD).
+func toFHIRDate(hl7Date string) string {
+	if len(hl7Date) != 8 {
+		return ""
+	}
+	return hl7Date[0:4] + "-" + hl7Date[4:6] + "-" + hl7Date[6:8]
+}
+
+// fromFHIRDate converts a FHIR date (YYYY-MM-DD) back to an HL7 DT
+// value (YYYYMMDD).
+func fromFHIRDate(fhirDate string) string {
+	return strings.ReplaceAll(fhirDate, "-", "")
+}
+
+// v2Sex maps a FHIR AdministrativeGender back to the closest HL7 v2
+// table 0001 code.
+func v2Sex(gender string) string {
+	switch gender {
+	case "male":
+		return "M"
+	case "female":
+		return "F"
+	case "other":
+		return "O"
+	default:
+		return "U"
+	}
+}
@@ -0,0 +1,69 @@
+package fhir
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+func TestToFHIRBundleThenFromFHIRBundlePreservesMessageType(t *testing.T) {
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|LAB|FAC|||20240101120000||ORU^R01|MSG1|P|2.5\rPID|||12345||DOE^JANE\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	bundle, err := ToFHIRBundle(msg)
+	if err != nil {
+		t.Fatalf("ToFHIRBundle: %v", err)
+	}
+
+	back, err := FromFHIRBundle(bundle)
+	if err != nil {
+		t.Fatalf("FromFHIRBundle: %v", err)
+	}
+	backMSH, ok := back.MSH()
+	if !ok {
+		t.Fatalf("converted message has no MSH")
+	}
+	if backMSH.MessageType() != "ORU" || backMSH.TriggerEvent() != "R01" {
+		t.Errorf("message type = %s^%s, want ORU^R01", backMSH.MessageType(), backMSH.TriggerEvent())
+	}
+}
+
+func TestFromFHIRBundleAcceptsJSONRoundTrippedResources(t *testing.T) {
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|LAB|FAC|||20240101120000||ADT^A01|MSG1|P|2.5\rPID|||12345||DOE^JANE\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	bundle, err := ToFHIRBundle(msg)
+	if err != nil {
+		t.Fatalf("ToFHIRBundle: %v", err)
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded Bundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	back, err := FromFHIRBundle(&decoded)
+	if err != nil {
+		t.Fatalf("FromFHIRBundle on JSON-decoded bundle: %v", err)
+	}
+	backMSH, ok := back.MSH()
+	if !ok || backMSH.MessageType() != "ADT" || backMSH.TriggerEvent() != "A01" {
+		t.Errorf("message type = %s^%s, want ADT^A01", backMSH.MessageType(), backMSH.TriggerEvent())
+	}
+}
+
+func TestFromFHIRBundleRequiresMessageHeader(t *testing.T) {
+	_, err := FromFHIRBundle(&Bundle{ResourceType: "Bundle", Type: "message"})
+	if err == nil {
+		t.Fatalf("expected an error for a bundle with no MessageHeader")
+	}
+}
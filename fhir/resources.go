@@ -0,0 +1,99 @@
+// Package fhir converts parsed HL7 v2 messages to and from FHIR R4
+// resources. Only the fields needed for that conversion are modeled;
+// this is not a general-purpose FHIR library.
+package fhir
+
+// Bundle is a FHIR Bundle of type "message", wrapping a MessageHeader
+// plus the resources derived from an HL7 v2 message.
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+// BundleEntry wraps a single resource in a Bundle.
+type BundleEntry struct {
+	Resource interface{} `json:"resource"`
+}
+
+// Identifier is a business identifier for a resource.
+type Identifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// HumanName is a FHIR HumanName.
+type HumanName struct {
+	Family string   `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+}
+
+// Coding is a single FHIR Coding.
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// CodeableConcept is a FHIR CodeableConcept.
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Reference is a FHIR Reference.
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+}
+
+// Patient is derived from the PID segment.
+type Patient struct {
+	ResourceType  string           `json:"resourceType"`
+	ID            string           `json:"id,omitempty"`
+	Identifier    []Identifier     `json:"identifier,omitempty"`
+	Name          []HumanName      `json:"name,omitempty"`
+	Gender        string           `json:"gender,omitempty"`
+	BirthDate     string           `json:"birthDate,omitempty"`
+	MaritalStatus *CodeableConcept `json:"maritalStatus,omitempty"`
+}
+
+// Encounter is derived from the PV1 segment.
+type Encounter struct {
+	ResourceType string     `json:"resourceType"`
+	ID           string     `json:"id,omitempty"`
+	Status       string     `json:"status"`
+	Class        Coding     `json:"class,omitempty"`
+	Subject      *Reference `json:"subject,omitempty"`
+}
+
+// Observation is derived from an OBX segment.
+type Observation struct {
+	ResourceType string          `json:"resourceType"`
+	ID           string          `json:"id,omitempty"`
+	Status       string          `json:"status"`
+	Code         CodeableConcept `json:"code"`
+	Subject      *Reference      `json:"subject,omitempty"`
+	ValueString  string          `json:"valueString,omitempty"`
+}
+
+// DiagnosticReport is derived from the OBR segment.
+type DiagnosticReport struct {
+	ResourceType string          `json:"resourceType"`
+	ID           string          `json:"id,omitempty"`
+	Status       string          `json:"status"`
+	Code         CodeableConcept `json:"code"`
+	Subject      *Reference      `json:"subject,omitempty"`
+}
+
+// MessageHeader is derived from the MSH segment.
+type MessageHeader struct {
+	ResourceType string        `json:"resourceType"`
+	ID           string        `json:"id,omitempty"`
+	EventCoding  Coding        `json:"eventCoding"`
+	Source       MessageSource `json:"source"`
+}
+
+// MessageSource describes the sending application of a MessageHeader.
+type MessageSource struct {
+	Name string `json:"name,omitempty"`
+}
@@ -0,0 +1,170 @@
+package hl7
+
+import "strings"
+
+// EncodingChars holds the component/repetition/escape/sub-component
+// separators declared in MSH-2 of a message (e.g. "^~\&"). Messages
+// built in memory via AddSegment use DefaultEncodingChars.
+type EncodingChars struct {
+	Component    byte
+	Repetition   byte
+	Escape       byte
+	SubComponent byte
+}
+
+// DefaultEncodingChars returns the standard HL7 encoding characters
+// ("^~\&") used when a message does not declare its own in MSH-2.
+func DefaultEncodingChars() EncodingChars {
+	return EncodingChars{
+		Component:    '^',
+		Repetition:   '~',
+		Escape:       '\\',
+		SubComponent: '&',
+	}
+}
+
+// encodingCharsFromMSH2 parses the raw MSH-2 value (e.g. "^~\&") into
+// an EncodingChars, falling back to the default for any character it
+// doesn't find.
+func encodingCharsFromMSH2(raw string) EncodingChars {
+	enc := DefaultEncodingChars()
+	if len(raw) > 0 {
+		enc.Component = raw[0]
+	}
+	if len(raw) > 1 {
+		enc.Repetition = raw[1]
+	}
+	if len(raw) > 2 {
+		enc.Escape = raw[2]
+	}
+	if len(raw) > 3 {
+		enc.SubComponent = raw[3]
+	}
+	return enc
+}
+
+// Component is the smallest addressable piece of a field that still
+// carries structure: a list of sub-components.
+type Component struct {
+	Raw           string
+	SubComponents []string
+}
+
+// Repetition is one occurrence of a repeating field, made up of
+// components.
+type Repetition struct {
+	Raw        string
+	Components []Component
+}
+
+// Field is a single HL7 field, which may repeat (Field-1~Field-2~...)
+// and whose repetitions are themselves split into components and
+// sub-components.
+type Field struct {
+	Raw         string
+	Repetitions []Repetition
+}
+
+// ParseField splits raw field text into repetitions/components/
+// sub-components using enc, unescaping \F\ \S\ \R\ \E\ \T\ sequences.
+func ParseField(raw string, enc EncodingChars) Field {
+	field := Field{Raw: raw}
+	for _, repRaw := range splitByte(raw, enc.Repetition, enc.Escape) {
+		rep := Repetition{Raw: repRaw}
+		for _, compRaw := range splitByte(repRaw, enc.Component, enc.Escape) {
+			comp := Component{Raw: compRaw}
+			for _, subRaw := range splitByte(compRaw, enc.SubComponent, enc.Escape) {
+				comp.SubComponents = append(comp.SubComponents, unescape(subRaw, enc))
+			}
+			rep.Components = append(rep.Components, comp)
+		}
+		field.Repetitions = append(field.Repetitions, rep)
+	}
+	return field
+}
+
+// Encode rebuilds the pipe-delimited representation of the field from
+// its structured parts, using enc as the separator set.
+func (f Field) Encode(enc EncodingChars) string {
+	reps := make([]string, len(f.Repetitions))
+	for i, rep := range f.Repetitions {
+		comps := make([]string, len(rep.Components))
+		for j, comp := range rep.Components {
+			comps[j] = strings.Join(comp.SubComponents, string(enc.SubComponent))
+		}
+		reps[i] = strings.Join(comps, string(enc.Component))
+	}
+	return strings.Join(reps, string(enc.Repetition))
+}
+
+// splitByte splits s on sep, but not on a sep byte that is preceded by
+// an unescaped escape character.
+func splitByte(s string, sep, escape byte) []string {
+	if s == "" {
+		return []string{""}
+	}
+
+	var parts []string
+	var current strings.Builder
+	inEscape := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inEscape:
+			current.WriteByte(c)
+			if c == escape {
+				inEscape = false
+			}
+		case c == escape:
+			current.WriteByte(c)
+			inEscape = true
+		case c == sep:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	parts = append(parts, current.String())
+	return parts
+}
+
+// unescape resolves the standard HL7 escape sequences (\F\, \S\, \R\,
+// \E\, \T\) within an already-isolated sub-component.
+func unescape(s string, enc EncodingChars) string {
+	if !strings.ContainsRune(s, rune(enc.Escape)) {
+		return s
+	}
+
+	esc := string(enc.Escape)
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != enc.Escape {
+			out.WriteByte(s[i])
+			continue
+		}
+		end := strings.IndexByte(s[i+1:], enc.Escape)
+		if end < 0 {
+			out.WriteByte(s[i])
+			continue
+		}
+		code := s[i+1 : i+1+end]
+		switch code {
+		case "F":
+			out.WriteByte(FieldSeparator[0])
+		case "S":
+			out.WriteByte(enc.Component)
+		case "R":
+			out.WriteByte(enc.Repetition)
+		case "T":
+			out.WriteByte(enc.SubComponent)
+		case "E":
+			out.WriteByte(enc.Escape)
+		default:
+			out.WriteString(esc + code + esc)
+		}
+		i += end + 1
+	}
+	return out.String()
+}
@@ -0,0 +1,40 @@
+package hl7
+
+import "testing"
+
+func TestParseFieldRepetitionsAndComponents(t *testing.T) {
+	enc := DefaultEncodingChars()
+	field := ParseField("A^B~C^D", enc)
+
+	if len(field.Repetitions) != 2 {
+		t.Fatalf("got %d repetitions, want 2", len(field.Repetitions))
+	}
+	if got := field.Repetitions[0].Components[0].Raw; got != "A" {
+		t.Errorf("rep0 comp0 = %q, want A", got)
+	}
+	if got := field.Repetitions[1].Components[1].Raw; got != "D" {
+		t.Errorf("rep1 comp1 = %q, want D", got)
+	}
+}
+
+func TestParseFieldEscapedSeparatorIsNotSplitOn(t *testing.T) {
+	enc := DefaultEncodingChars()
+	field := ParseField(`A\S\B^C`, enc)
+
+	if len(field.Repetitions[0].Components) != 2 {
+		t.Fatalf("got %d components, want 2 (escaped ^ must not split)", len(field.Repetitions[0].Components))
+	}
+	if got := field.Repetitions[0].Components[0].SubComponents[0]; got != "A^B" {
+		t.Errorf("component 0 = %q, want A^B (escaped component separator unescaped to literal)", got)
+	}
+}
+
+func TestFieldEncodeRoundTrip(t *testing.T) {
+	enc := DefaultEncodingChars()
+	raw := "A^B~C^D"
+	field := ParseField(raw, enc)
+
+	if got := field.Encode(enc); got != raw {
+		t.Errorf("Encode() = %q, want %q", got, raw)
+	}
+}
@@ -0,0 +1,156 @@
+// Package hl7 provides the core HL7 v2 message model shared by the HTTP
+// API, the MLLP transport, and anything else that needs to parse or
+// generate HL7 pipe-delimited messages.
+package hl7
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+)
+
+// Constants for HL7 message structure
+const (
+	SegmentSeparator      = "\r"
+	FieldSeparator        = "|"
+	ComponentSeparator    = "^"
+	SubComponentSeparator = "&"
+	RepetitionSeparator   = "~"
+	EscapeCharacter       = "\\"
+)
+
+// HL7Message represents a complete HL7 message
+type HL7Message struct {
+	Segments []Segment
+}
+
+// Segment represents an HL7 segment. Fields are parsed into their
+// repetition/component/sub-component structure using the encoding
+// characters in effect for the message (MSH-2), accessible through Get
+// and Set.
+type Segment struct {
+	Type   string
+	Fields []Field
+
+	enc EncodingChars
+}
+
+// NewHL7Message creates a new HL7 message
+func NewHL7Message() *HL7Message {
+	return &HL7Message{
+		Segments: make([]Segment, 0),
+	}
+}
+
+// AddSegment adds a new segment to the message, built from plain field
+// strings. Fields are parsed using the standard HL7 encoding characters
+// ("^~\&"); use Segment.Set afterwards if a field needs a different
+// encoding.
+func (m *HL7Message) AddSegment(segmentType string, fields ...string) {
+	enc := DefaultEncodingChars()
+	segment := Segment{
+		Type: segmentType,
+		enc:  enc,
+	}
+	for _, raw := range fields {
+		segment.Fields = append(segment.Fields, ParseField(raw, enc))
+	}
+	m.Segments = append(m.Segments, segment)
+}
+
+// FirstSegment returns the first segment of the given type, if any.
+func (m *HL7Message) FirstSegment(segmentType string) (*Segment, bool) {
+	for i := range m.Segments {
+		if m.Segments[i].Type == segmentType {
+			return &m.Segments[i], true
+		}
+	}
+	return nil, false
+}
+
+// SegmentsOfType returns all segments of the given type, e.g. every OBX
+// in a result message.
+func (m *HL7Message) SegmentsOfType(segmentType string) []*Segment {
+	var segs []*Segment
+	for i := range m.Segments {
+		if m.Segments[i].Type == segmentType {
+			segs = append(segs, &m.Segments[i])
+		}
+	}
+	return segs
+}
+
+// GenerateMessage converts the HL7Message to a string
+func (m *HL7Message) GenerateMessage() string {
+	var messageBuilder strings.Builder
+
+	for _, segment := range m.Segments {
+		messageBuilder.WriteString(segment.Type)
+		for _, field := range segment.Fields {
+			messageBuilder.WriteString(FieldSeparator)
+			messageBuilder.WriteString(field.Raw)
+		}
+		messageBuilder.WriteString(SegmentSeparator)
+	}
+
+	return messageBuilder.String()
+}
+
+// ParseHL7Message parses an HL7 message string into an HL7Message struct
+func ParseHL7Message(messageStr string) (*HL7Message, error) {
+	message := NewHL7Message()
+
+	scanner := bufio.NewScanner(strings.NewReader(messageStr))
+	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := strings.Index(string(data), SegmentSeparator); i >= 0 {
+			return i + 1, data[0:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+
+	type rawSegment struct {
+		segType string
+		fields  []string
+	}
+	var rawSegments []rawSegment
+	enc := DefaultEncodingChars()
+
+	for scanner.Scan() {
+		segmentStr := scanner.Text()
+		if len(segmentStr) == 0 {
+			continue
+		}
+
+		fields := strings.Split(segmentStr, FieldSeparator)
+		if len(fields) < 1 {
+			return nil, errors.New("invalid segment format")
+		}
+
+		segType := fields[0]
+		segFields := fields[1:]
+		if segType == "MSH" && len(segFields) > 0 {
+			enc = encodingCharsFromMSH2(segFields[0])
+		}
+		rawSegments = append(rawSegments, rawSegment{segType: segType, fields: segFields})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, raw := range rawSegments {
+		segment := Segment{Type: raw.segType, enc: enc}
+		for _, f := range raw.fields {
+			segment.Fields = append(segment.Fields, ParseField(f, enc))
+		}
+		message.Segments = append(message.Segments, segment)
+	}
+
+	return message, nil
+}
@@ -0,0 +1,187 @@
+package hl7
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Get returns the value addressed by the 1-based HL7 field/repetition/
+// component/sub-component indices, e.g. Get(5, 1, 1, 0) for PID-5.1
+// (patient family name). A repIdx, compIdx, or subIdx of 0 (or less)
+// selects "the whole thing at this level" - Get(5, 1, 0, 0) returns the
+// raw first repetition of field 5 without splitting it into components.
+// ok is false if the path doesn't exist in this segment.
+func (s *Segment) Get(fieldIdx, repIdx, compIdx, subIdx int) (string, bool) {
+	if s.Type == "MSH" && fieldIdx == 1 {
+		return FieldSeparator, true
+	}
+
+	idx, ok := s.arrayIndex(fieldIdx)
+	if !ok || idx >= len(s.Fields) {
+		return "", false
+	}
+	field := s.Fields[idx]
+
+	// MSH-2 is the literal encoding-characters string, not a
+	// component-delimited value - it defines those very delimiters, so
+	// splitting it with itself would corrupt it. Always return it whole.
+	if s.Type == "MSH" && fieldIdx == 2 {
+		return field.Raw, true
+	}
+
+	if repIdx <= 0 {
+		repIdx = 1
+	}
+	if repIdx-1 >= len(field.Repetitions) {
+		return "", false
+	}
+	rep := field.Repetitions[repIdx-1]
+
+	if compIdx <= 0 {
+		return rep.Raw, true
+	}
+	if compIdx-1 >= len(rep.Components) {
+		return "", false
+	}
+	comp := rep.Components[compIdx-1]
+
+	if subIdx <= 0 {
+		return comp.Raw, true
+	}
+	if subIdx-1 >= len(comp.SubComponents) {
+		return "", false
+	}
+	return comp.SubComponents[subIdx-1], true
+}
+
+// Set writes value at the given 1-based field/repetition/component/
+// sub-component indices, growing the segment as needed. repIdx,
+// compIdx, and subIdx of 0 (or less) default to 1. MSH-1 (the field
+// separator) cannot be changed this way and is a no-op.
+func (s *Segment) Set(fieldIdx, repIdx, compIdx, subIdx int, value string) {
+	if s.Type == "MSH" && fieldIdx == 1 {
+		return
+	}
+	idx, ok := s.arrayIndex(fieldIdx)
+	if !ok {
+		return
+	}
+	if idx >= len(s.Fields) {
+		grown := make([]Field, idx+1)
+		copy(grown, s.Fields)
+		s.Fields = grown
+	}
+	if repIdx <= 0 {
+		repIdx = 1
+	}
+	if compIdx <= 0 {
+		compIdx = 1
+	}
+	if subIdx <= 0 {
+		subIdx = 1
+	}
+
+	field := &s.Fields[idx]
+	for len(field.Repetitions) < repIdx {
+		field.Repetitions = append(field.Repetitions, Repetition{})
+	}
+	rep := &field.Repetitions[repIdx-1]
+	for len(rep.Components) < compIdx {
+		rep.Components = append(rep.Components, Component{})
+	}
+	comp := &rep.Components[compIdx-1]
+	for len(comp.SubComponents) < subIdx {
+		comp.SubComponents = append(comp.SubComponents, "")
+	}
+	comp.SubComponents[subIdx-1] = value
+
+	enc := s.enc
+	comp.Raw = strings.Join(comp.SubComponents, string(enc.SubComponent))
+	rep.Raw = joinRaw(rep.Components, enc.Component)
+	field.Raw = joinFieldRaw(field.Repetitions, enc.Repetition)
+}
+
+// arrayIndex converts a 1-based HL7 field number to an index into
+// s.Fields, accounting for MSH, whose first stored field is MSH-2 (the
+// encoding characters) rather than MSH-1.
+func (s *Segment) arrayIndex(fieldIdx int) (int, bool) {
+	idx := fieldIdx - 1
+	if s.Type == "MSH" {
+		idx = fieldIdx - 2
+	}
+	if idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+func joinRaw(components []Component, sep byte) string {
+	parts := make([]string, len(components))
+	for i, c := range components {
+		parts[i] = c.Raw
+	}
+	return strings.Join(parts, string(sep))
+}
+
+func joinFieldRaw(reps []Repetition, sep byte) string {
+	parts := make([]string, len(reps))
+	for i, r := range reps {
+		parts[i] = r.Raw
+	}
+	return strings.Join(parts, string(sep))
+}
+
+// GetPath resolves a terse HL7 location string such as "PID-5.1.1"
+// (segment-field.component.subcomponent) against the first matching
+// segment in the message. An optional repetition index may be given as
+// "PID-5[2].1.1"; it defaults to the first repetition.
+func (m *HL7Message) GetPath(path string) (string, bool) {
+	segType, rest := path, ""
+	if i := strings.IndexByte(path, '-'); i >= 0 {
+		segType, rest = path[:i], path[i+1:]
+	}
+	if rest == "" {
+		return "", false
+	}
+
+	seg, ok := m.FirstSegment(segType)
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.Split(rest, ".")
+
+	fieldPart := parts[0]
+	repIdx := 1
+	if b := strings.IndexByte(fieldPart, '['); b >= 0 {
+		e := strings.IndexByte(fieldPart, ']')
+		if e < 0 {
+			return "", false
+		}
+		rep, err := strconv.Atoi(fieldPart[b+1 : e])
+		if err != nil {
+			return "", false
+		}
+		repIdx = rep
+		fieldPart = fieldPart[:b]
+	}
+
+	fieldIdx, err := strconv.Atoi(fieldPart)
+	if err != nil {
+		return "", false
+	}
+
+	compIdx, subIdx := 0, 0
+	if len(parts) > 1 {
+		if compIdx, err = strconv.Atoi(parts[1]); err != nil {
+			return "", false
+		}
+	}
+	if len(parts) > 2 {
+		if subIdx, err = strconv.Atoi(parts[2]); err != nil {
+			return "", false
+		}
+	}
+
+	return seg.Get(fieldIdx, repIdx, compIdx, subIdx)
+}
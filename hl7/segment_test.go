@@ -0,0 +1,71 @@
+package hl7
+
+import "testing"
+
+func TestSegmentGetMSH2ReturnsEncodingCharsWhole(t *testing.T) {
+	msg, err := ParseHL7Message("MSH|^~\\&|SEND|FAC|RECV|RECV_FAC|20240101120000||ADT^A01|MSG001|P|2.5\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+	msh, ok := msg.MSH()
+	if !ok {
+		t.Fatalf("expected MSH segment")
+	}
+
+	v, ok := msh.Get(2, 1, 1, 0)
+	if !ok {
+		t.Fatalf("MSH-2 not found")
+	}
+	if v != "^~\\&" {
+		t.Fatalf("MSH-2 = %q, want %q (encoding chars must not be re-split using themselves)", v, "^~\\&")
+	}
+}
+
+func TestSegmentGetMSH1IsFieldSeparator(t *testing.T) {
+	msg, err := ParseHL7Message("MSH|^~\\&|SEND|FAC||||||MSG001|P|2.5\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+	msh, ok := msg.MSH()
+	if !ok {
+		t.Fatalf("expected MSH segment")
+	}
+
+	v, ok := msh.Get(1, 1, 1, 0)
+	if !ok || v != "|" {
+		t.Fatalf("MSH-1 = %q, %v; want %q, true", v, ok, "|")
+	}
+}
+
+func TestSegmentGetComponentAndSubComponent(t *testing.T) {
+	msg, err := ParseHL7Message("MSH|^~\\&|SEND|FAC||||||MSG001|P|2.5\rPID|||12345||DOE^JOHN^A&B\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+	pid, ok := msg.PID()
+	if !ok {
+		t.Fatalf("expected PID segment")
+	}
+
+	if v, _ := pid.Get(5, 1, 1, 0); v != "DOE" {
+		t.Errorf("PID-5.1 = %q, want DOE", v)
+	}
+	if v, _ := pid.Get(5, 1, 2, 0); v != "JOHN" {
+		t.Errorf("PID-5.2 = %q, want JOHN", v)
+	}
+	if v, _ := pid.Get(5, 1, 3, 2); v != "B" {
+		t.Errorf("PID-5.3.2 = %q, want B", v)
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	msg, err := ParseHL7Message("MSH|^~\\&|SEND|FAC||||||MSG001|P|2.5\rPID|||12345||DOE^JOHN\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	v, ok := msg.GetPath("PID-5.2")
+	if !ok || v != "JOHN" {
+		t.Fatalf("GetPath(PID-5.2) = %q, %v; want JOHN, true", v, ok)
+	}
+}
@@ -0,0 +1,98 @@
+package hl7
+
+// This file provides thin, named getters over the common segment types
+// so callers don't have to remember raw field numbers. Each wrapper
+// embeds *Segment, so Get/Set and the rest of the Segment API remain
+// available directly.
+
+// MSH wraps the message header segment.
+type MSH struct{ *Segment }
+
+// MSH returns the message header segment, if present.
+func (m *HL7Message) MSH() (MSH, bool) {
+	seg, ok := m.FirstSegment("MSH")
+	return MSH{seg}, ok
+}
+
+func (h MSH) SendingApplication() string   { v, _ := h.Get(3, 1, 1, 0); return v }
+func (h MSH) SendingFacility() string      { v, _ := h.Get(4, 1, 1, 0); return v }
+func (h MSH) ReceivingApplication() string { v, _ := h.Get(5, 1, 1, 0); return v }
+func (h MSH) ReceivingFacility() string    { v, _ := h.Get(6, 1, 1, 0); return v }
+func (h MSH) DateTimeOfMessage() string    { v, _ := h.Get(7, 1, 1, 0); return v }
+func (h MSH) MessageType() string          { v, _ := h.Get(9, 1, 1, 0); return v }
+func (h MSH) TriggerEvent() string         { v, _ := h.Get(9, 1, 2, 0); return v }
+func (h MSH) MessageControlID() string     { v, _ := h.Get(10, 1, 1, 0); return v }
+func (h MSH) ProcessingID() string         { v, _ := h.Get(11, 1, 1, 0); return v }
+func (h MSH) VersionID() string            { v, _ := h.Get(12, 1, 1, 0); return v }
+
+// PID wraps the patient identification segment.
+type PID struct{ *Segment }
+
+// PID returns the patient identification segment, if present.
+func (m *HL7Message) PID() (PID, bool) {
+	seg, ok := m.FirstSegment("PID")
+	return PID{seg}, ok
+}
+
+func (p PID) PatientID() string     { v, _ := p.Get(3, 1, 1, 0); return v }
+func (p PID) FamilyName() string    { v, _ := p.Get(5, 1, 1, 0); return v }
+func (p PID) GivenName() string     { v, _ := p.Get(5, 1, 2, 0); return v }
+func (p PID) DateOfBirth() string   { v, _ := p.Get(7, 1, 1, 0); return v }
+func (p PID) Sex() string           { v, _ := p.Get(8, 1, 1, 0); return v }
+func (p PID) MaritalStatus() string { v, _ := p.Get(16, 1, 1, 0); return v }
+
+// PatientName returns the patient's name as "Family Given", omitting
+// the space if the given name is absent.
+func (p PID) PatientName() string {
+	family, given := p.FamilyName(), p.GivenName()
+	if given == "" {
+		return family
+	}
+	return family + " " + given
+}
+
+// PV1 wraps the patient visit segment.
+type PV1 struct{ *Segment }
+
+// PV1 returns the patient visit segment, if present.
+func (m *HL7Message) PV1() (PV1, bool) {
+	seg, ok := m.FirstSegment("PV1")
+	return PV1{seg}, ok
+}
+
+func (p PV1) PatientClass() string     { v, _ := p.Get(2, 1, 1, 0); return v }
+func (p PV1) AssignedLocation() string { v, _ := p.Get(3, 1, 1, 0); return v }
+func (p PV1) AttendingDoctor() string  { v, _ := p.Get(7, 1, 1, 0); return v }
+func (p PV1) VisitNumber() string      { v, _ := p.Get(19, 1, 1, 0); return v }
+
+// OBX wraps an observation/result segment. A message may contain many
+// OBX segments; use HL7Message.OBXSegments for all of them.
+type OBX struct{ *Segment }
+
+// OBXSegments returns every OBX segment in the message, in order.
+func (m *HL7Message) OBXSegments() []OBX {
+	segs := m.SegmentsOfType("OBX")
+	result := make([]OBX, len(segs))
+	for i, s := range segs {
+		result[i] = OBX{s}
+	}
+	return result
+}
+
+func (o OBX) ValueType() string             { v, _ := o.Get(2, 1, 1, 0); return v }
+func (o OBX) ObservationIdentifier() string { v, _ := o.Get(3, 1, 1, 0); return v }
+func (o OBX) ObservationValue() string      { v, _ := o.Get(5, 1, 1, 0); return v }
+func (o OBX) Units() string                 { v, _ := o.Get(6, 1, 1, 0); return v }
+func (o OBX) ResultStatus() string          { v, _ := o.Get(11, 1, 1, 0); return v }
+
+// OBR wraps an observation request segment.
+type OBR struct{ *Segment }
+
+// OBR returns the observation request segment, if present.
+func (m *HL7Message) OBR() (OBR, bool) {
+	seg, ok := m.FirstSegment("OBR")
+	return OBR{seg}, ok
+}
+
+func (o OBR) UniversalServiceID() string { v, _ := o.Get(4, 1, 1, 0); return v }
+func (o OBR) FillerOrderNumber() string  { v, _ := o.Get(3, 1, 1, 0); return v }
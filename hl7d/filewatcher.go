@@ -0,0 +1,147 @@
+package hl7d
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// FileWatcher ingests HL7 messages dropped as files into a directory,
+// one message per file or a batch file wrapped in FHS/BHS/BTS/FTS
+// envelope segments. Each file is moved into a "processed" or "failed"
+// subdirectory once handled, so it isn't picked up again.
+type FileWatcher struct {
+	// Dir is the directory to watch for new files.
+	Dir string
+	// Server handles each message found in a file.
+	Server *Server
+	// PollInterval controls how often Dir is scanned. Defaults to 1s.
+	PollInterval time.Duration
+	// Logger receives processing errors. Defaults to log.Default().
+	Logger *log.Logger
+}
+
+// Run polls Dir until ctx is canceled, processing any new files it
+// finds.
+func (fw *FileWatcher) Run(ctx context.Context) error {
+	interval := fw.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	logger := fw.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	if err := os.MkdirAll(filepath.Join(fw.Dir, "processed"), 0o755); err != nil {
+		return fmt.Errorf("hl7d: create processed dir: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(fw.Dir, "failed"), 0o755); err != nil {
+		return fmt.Errorf("hl7d: create failed dir: %w", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fw.scanOnce(ctx, logger)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (fw *FileWatcher) scanOnce(ctx context.Context, logger *log.Logger) {
+	entries, err := os.ReadDir(fw.Dir)
+	if err != nil {
+		logger.Printf("hl7d: filewatcher: reading %s: %v", fw.Dir, err)
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(fw.Dir, entry.Name())
+		if err := fw.processFile(ctx, path); err != nil {
+			logger.Printf("hl7d: filewatcher: %s: %v", path, err)
+			fw.moveTo(path, "failed")
+			continue
+		}
+		fw.moveTo(path, "processed")
+	}
+}
+
+func (fw *FileWatcher) processFile(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	for _, raw := range splitBatch(string(data)) {
+		msg, err := hl7.ParseHL7Message(raw)
+		if err != nil {
+			return fmt.Errorf("parse: %w", err)
+		}
+		if _, err := fw.Server.Handle(ctx, msg); err != nil {
+			return fmt.Errorf("handle: %w", err)
+		}
+	}
+	return nil
+}
+
+func (fw *FileWatcher) moveTo(path, subdir string) {
+	dest := filepath.Join(fw.Dir, subdir, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil && fw.Logger != nil {
+		fw.Logger.Printf("hl7d: filewatcher: moving %s to %s: %v", path, dest, err)
+	}
+}
+
+// splitBatch splits the contents of a file into one raw HL7 message
+// per MSH segment, discarding FHS/BHS/BTS/FTS batch envelope segments.
+// A file with no batch envelope and a single MSH is returned as one
+// message.
+func splitBatch(content string) []string {
+	var messages []string
+	var current []string
+
+	flush := func() {
+		if len(current) > 0 {
+			messages = append(messages, strings.Join(current, hl7.SegmentSeparator))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, hl7.SegmentSeparator) {
+		if line == "" {
+			continue
+		}
+		segType := line
+		if len(segType) > 3 {
+			segType = segType[:3]
+		}
+
+		switch segType {
+		case "FHS", "BHS", "BTS", "FTS":
+			continue
+		case "MSH":
+			flush()
+			current = append(current, line)
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	return messages
+}
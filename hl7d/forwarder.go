@@ -0,0 +1,129 @@
+package hl7d
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sodrety/hl7-middleware/dispatch"
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// ForwarderConfig configures the Forwarder middleware.
+type ForwarderConfig struct {
+	// Destinations are the downstream endpoints to fan out to, each
+	// either "http://host/path" or "mllp://host:port".
+	Destinations []string
+	// MaxRetries is the number of retry attempts per destination after
+	// the initial send. Defaults to 2.
+	MaxRetries int
+	// RetryDelay is the pause between attempts. Defaults to one second.
+	RetryDelay time.Duration
+	// DeadLetterDir, if set, receives a copy of any message that
+	// exhausts its retries for a destination.
+	DeadLetterDir string
+	// Logger receives forwarding failures. Defaults to log.Default().
+	Logger *log.Logger
+}
+
+// Forwarder returns middleware that, after next handles a message,
+// fans it out to every configured destination concurrently with
+// per-destination retry. Forwarding failures do not affect the
+// response returned to the original caller.
+func Forwarder(cfg ForwarderConfig) Middleware {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+			resp, err := next(ctx, msg)
+
+			for _, dest := range cfg.Destinations {
+				dest := dest
+				go func() {
+					if sendErr := sendWithRetry(dest, msg, cfg.MaxRetries, cfg.RetryDelay); sendErr != nil {
+						cfg.Logger.Printf("hl7d: forwarder: giving up on %s: %v", dest, sendErr)
+						deadLetter(cfg.DeadLetterDir, dest, msg, cfg.Logger)
+					}
+				}()
+			}
+
+			return resp, err
+		}
+	}
+}
+
+func sendWithRetry(dest string, msg *hl7.HL7Message, maxRetries int, delay time.Duration) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+		}
+		if lastErr = dispatch.Send(dest, msg); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+// deadLetter writes msg to dir, named after the destination it failed
+// to reach and the message's own control ID, so an operator can
+// inspect and replay it later.
+func deadLetter(dir, dest string, msg *hl7.HL7Message, logger *log.Logger) {
+	if dir == "" {
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logger.Printf("hl7d: forwarder: dead-letter dir %s: %v", dir, err)
+		return
+	}
+
+	controlID := "unknown"
+	if msh, ok := msg.MSH(); ok {
+		if id := msh.MessageControlID(); id != "" {
+			controlID = id
+		}
+	}
+
+	sum := sha1.Sum([]byte(dest))
+	name := fmt.Sprintf("%s-%s-%s.hl7", sanitizeFilenamePart(controlID), hex.EncodeToString(sum[:4]), time.Now().UTC().Format("20060102T150405"))
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path, []byte(msg.GenerateMessage()), 0o644); err != nil {
+		logger.Printf("hl7d: forwarder: writing dead letter %s: %v", path, err)
+	}
+}
+
+// sanitizeFilenamePart strips everything but a safe set of characters
+// from s, so attacker-controlled HL7 fields (e.g. MSH-10) can't be used
+// to escape DeadLetterDir via "../" path traversal when building a
+// dead-letter filename.
+func sanitizeFilenamePart(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-' || r == '_':
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "unknown"
+	}
+	if b.Len() > 64 {
+		return b.String()[:64]
+	}
+	return b.String()
+}
@@ -0,0 +1,22 @@
+package hl7d
+
+import "testing"
+
+func TestSanitizeFilenamePartStripsTraversal(t *testing.T) {
+	got := sanitizeFilenamePart("../../../../etc/passwd")
+	if got != "etcpasswd" {
+		t.Fatalf("sanitizeFilenamePart = %q, want %q", got, "etcpasswd")
+	}
+}
+
+func TestSanitizeFilenamePartEmptyFallsBackToUnknown(t *testing.T) {
+	if got := sanitizeFilenamePart("///"); got != "unknown" {
+		t.Fatalf("sanitizeFilenamePart(%q) = %q, want %q", "///", got, "unknown")
+	}
+}
+
+func TestSanitizeFilenamePartKeepsSafeCharacters(t *testing.T) {
+	if got := sanitizeFilenamePart("MSG-001_ok"); got != "MSG-001_ok" {
+		t.Fatalf("sanitizeFilenamePart = %q, want unchanged", got)
+	}
+}
@@ -0,0 +1,47 @@
+package hl7d
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// Logging returns middleware that logs each message's type and control
+// ID before invoking next, and the outcome afterwards.
+func Logging(logger *log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+			messageType, controlID := "unknown", "unknown"
+			if msh, ok := msg.MSH(); ok {
+				messageType = msh.MessageType() + "^" + msh.TriggerEvent()
+				controlID = msh.MessageControlID()
+			}
+
+			logger.Printf("hl7d: handling %s (control id %s)", messageType, controlID)
+			resp, err := next(ctx, msg)
+			if err != nil {
+				logger.Printf("hl7d: %s (control id %s) failed: %v", messageType, controlID, err)
+			} else {
+				logger.Printf("hl7d: %s (control id %s) handled", messageType, controlID)
+			}
+			return resp, err
+		}
+	}
+}
+
+// Recover returns middleware that converts a panic in next into an
+// error, so one malformed message can't take down a shared listener.
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, msg *hl7.HL7Message) (resp *hl7.HL7Message, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("hl7d: handler panicked: %v", r)
+				}
+			}()
+			return next(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package hl7d
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// Router dispatches a message to a Handler registered for its MSH-9
+// message type, analogous to http.ServeMux dispatching on path.
+// Handlers can be registered once and reused across transports by
+// passing the Router's ServeHL7 method (or the Router itself via
+// HandlerFunc) as a Server's handler.
+type Router struct {
+	routes    map[string]Handler
+	wildcards map[string]Handler
+	notFound  Handler
+}
+
+// NewRouter creates an empty Router. Use NotFound to set a fallback for
+// unregistered message types; by default an unmatched message returns
+// an error.
+func NewRouter() *Router {
+	return &Router{
+		routes:    make(map[string]Handler),
+		wildcards: make(map[string]Handler),
+		notFound: func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+			return nil, fmt.Errorf("hl7d: no handler registered for this message type")
+		},
+	}
+}
+
+// Handle registers handler for an exact message type, e.g. "ADT^A01".
+// Use "ADT^*" to match any trigger event for the ADT message type.
+func (r *Router) Handle(messageType string, handler Handler) {
+	if n := len(messageType); n >= 2 && messageType[n-2:] == "^*" {
+		r.wildcards[messageType[:n-2]] = handler
+		return
+	}
+	r.routes[messageType] = handler
+}
+
+// NotFound sets the handler used when no route matches.
+func (r *Router) NotFound(handler Handler) {
+	r.notFound = handler
+}
+
+// ServeHL7 implements Handler, dispatching on the message's MSH-9 type
+// and trigger event.
+func (r *Router) ServeHL7(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+	msh, ok := msg.MSH()
+	if !ok {
+		return r.notFound(ctx, msg)
+	}
+
+	messageType := msh.MessageType() + "^" + msh.TriggerEvent()
+	if handler, ok := r.routes[messageType]; ok {
+		return handler(ctx, msg)
+	}
+	if handler, ok := r.wildcards[msh.MessageType()]; ok {
+		return handler(ctx, msg)
+	}
+	return r.notFound(ctx, msg)
+}
@@ -0,0 +1,89 @@
+package hl7d
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+func parseOrFatal(t *testing.T, raw string) *hl7.HL7Message {
+	t.Helper()
+	msg, err := hl7.ParseHL7Message(raw)
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+	return msg
+}
+
+func TestRouterExactMatch(t *testing.T) {
+	r := NewRouter()
+	called := false
+	r.Handle("ADT^A01", func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+		called = true
+		return nil, nil
+	})
+
+	msg := parseOrFatal(t, "MSH|^~\\&|A|B|||||ADT^A01|MSG1|P|2.5\r")
+	if _, err := r.ServeHL7(context.Background(), msg); err != nil {
+		t.Fatalf("ServeHL7: %v", err)
+	}
+	if !called {
+		t.Fatalf("exact-match handler was not called")
+	}
+}
+
+func TestRouterWildcardMatch(t *testing.T) {
+	r := NewRouter()
+	var gotType string
+	r.Handle("ADT^*", func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+		msh, _ := msg.MSH()
+		gotType = msh.TriggerEvent()
+		return nil, nil
+	})
+
+	msg := parseOrFatal(t, "MSH|^~\\&|A|B|||||ADT^A08|MSG1|P|2.5\r")
+	if _, err := r.ServeHL7(context.Background(), msg); err != nil {
+		t.Fatalf("ServeHL7: %v", err)
+	}
+	if gotType != "A08" {
+		t.Fatalf("wildcard handler saw trigger %q, want A08", gotType)
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	r := NewRouter()
+	msg := parseOrFatal(t, "MSH|^~\\&|A|B|||||ORM^O01|MSG1|P|2.5\r")
+
+	if _, err := r.ServeHL7(context.Background(), msg); err == nil {
+		t.Fatalf("expected an error for an unregistered message type")
+	}
+}
+
+func TestSplitBatchSingleMessage(t *testing.T) {
+	got := splitBatch("MSH|^~\\&|A|B\rPID|||1\r")
+	if len(got) != 1 {
+		t.Fatalf("got %d messages, want 1", len(got))
+	}
+}
+
+func TestSplitBatchStripsEnvelopeAndSplitsOnMSH(t *testing.T) {
+	content := "FHS|^~\\&\rBHS|^~\\&\r" +
+		"MSH|^~\\&|A|B|||||ADT^A01|MSG1|P|2.5\rPID|||1\r" +
+		"MSH|^~\\&|A|B|||||ADT^A01|MSG2|P|2.5\rPID|||2\r" +
+		"BTS|2\rFTS|1\r"
+
+	got := splitBatch(content)
+	if len(got) != 2 {
+		t.Fatalf("got %d messages, want 2", len(got))
+	}
+	for _, raw := range got {
+		msg, err := hl7.ParseHL7Message(raw)
+		if err != nil {
+			t.Fatalf("ParseHL7Message(%q): %v", raw, err)
+		}
+		if _, ok := msg.FirstSegment("FHS"); ok {
+			t.Errorf("split message retained the FHS envelope segment")
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Package hl7d provides a transport-independent HL7 processing
+// pipeline: a Handler/Middleware chain plus a message-type Router,
+// shared by the HTTP, MLLP, and file-drop transports.
+package hl7d
+
+import (
+	"context"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// Handler processes one HL7 message and returns the message to send
+// back to the caller (typically an ACK/NAK), or an error.
+type Handler func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error)
+
+// Middleware wraps a Handler to add cross-cutting behavior (logging,
+// tracing, authentication, rate-limiting, forwarding, ...) without the
+// handler itself needing to know about it.
+type Middleware func(next Handler) Handler
+
+// Server is the entry point transports call into: it applies the
+// configured middleware chain around a Router (or any Handler).
+type Server struct {
+	handler    Handler
+	middleware []Middleware
+}
+
+// NewServer creates a Server that dispatches to handler once the
+// middleware chain (registered via Use) has run.
+func NewServer(handler Handler) *Server {
+	return &Server{handler: handler}
+}
+
+// Use appends mw to the middleware chain. Middleware runs in the order
+// it was added, outermost first.
+func (s *Server) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// Handle runs msg through the middleware chain and the underlying
+// handler. It is safe to call concurrently from multiple transports.
+func (s *Server) Handle(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+	h := s.handler
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		h = s.middleware[i](h)
+	}
+	return h(ctx, msg)
+}
@@ -0,0 +1,44 @@
+package hl7d
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// HTTPHandler adapts srv to an http.HandlerFunc that accepts a raw HL7
+// v2 message as the request body and writes the handler's response
+// back as a raw HL7 v2 message, mirroring the MLLP wire format minus
+// the framing bytes.
+func HTTPHandler(srv *Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Error reading request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		msg, err := hl7.ParseHL7Message(string(body))
+		if err != nil {
+			http.Error(w, "Error parsing HL7 message: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err := srv.Handle(r.Context(), msg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Header().Set("Content-Type", "x-application/hl7-v2+er7")
+		if resp != nil {
+			w.Write([]byte(resp.GenerateMessage()))
+		}
+	}
+}
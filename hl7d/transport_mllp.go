@@ -0,0 +1,25 @@
+package hl7d
+
+import (
+	"context"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+	"github.com/sodrety/hl7-middleware/mllp"
+)
+
+// MLLPHandler adapts srv to an mllp.Handler, so the same middleware
+// chain and Router used by the HTTP transport can serve MLLP
+// connections. If srv's handler returns an error, a NAK ("AE") is sent
+// back instead of a dropped connection.
+func MLLPHandler(srv *Server) mllp.Handler {
+	return func(msg *hl7.HL7Message) *hl7.HL7Message {
+		resp, err := srv.Handle(context.Background(), msg)
+		if err != nil {
+			return mllp.GenerateACK(msg, "AE", err.Error())
+		}
+		if resp != nil {
+			return resp
+		}
+		return mllp.GenerateACK(msg, "AA", "")
+	}
+}
@@ -1,17 +1,24 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/sodrety/hl7-middleware/fhir"
+	"github.com/sodrety/hl7-middleware/hl7"
+	"github.com/sodrety/hl7-middleware/hl7d"
+	"github.com/sodrety/hl7-middleware/mllp"
+	"github.com/sodrety/hl7-middleware/store"
+	"github.com/sodrety/hl7-middleware/validation"
 )
 
 // Version information (will be set during build)
@@ -20,146 +27,256 @@ var (
 	BuildDate = "unknown"
 )
 
-// Constants for HL7 message structure
-const (
-	SegmentSeparator      = "\r"
-	FieldSeparator        = "|"
-	ComponentSeparator    = "^"
-	SubComponentSeparator = "&"
-	RepetitionSeparator   = "~"
-	EscapeCharacter       = "\\"
-)
-
-// HL7Message represents a complete HL7 message
-type HL7Message struct {
-	Segments []Segment
-}
-
-// Segment represents an HL7 segment
-type Segment struct {
-	Type   string
-	Fields []string
-}
-
 // HTTPResponse represents the standard API response
 type HTTPResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    *HL7Message `json:"data,omitempty"`
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    *hl7.HL7Message `json:"data,omitempty"`
 }
 
-// NewHL7Message creates a new HL7 message
-func NewHL7Message() *HL7Message {
-	return &HL7Message{
-		Segments: make([]Segment, 0),
+// HTTP Server handlers
+
+// handleParseHL7 handles POST requests to parse HL7 messages
+func handleParseHL7(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONResponse(w, false, "Method not allowed", nil, http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		sendJSONResponse(w, false, "Error reading request body", nil, http.StatusBadRequest)
+		return
 	}
-}
 
-// AddSegment adds a new segment to the message
-func (m *HL7Message) AddSegment(segmentType string, fields ...string) {
-	segment := Segment{
-		Type:   segmentType,
-		Fields: fields,
+	message, err := hl7.ParseHL7Message(string(body))
+	if err != nil {
+		sendJSONResponse(w, false, fmt.Sprintf("Error parsing HL7 message: %v", err), nil, http.StatusBadRequest)
+		return
 	}
-	m.Segments = append(m.Segments, segment)
-}
 
-// GenerateMessage converts the HL7Message to a string
-func (m *HL7Message) GenerateMessage() string {
-	var messageBuilder strings.Builder
+	sendJSONResponse(w, true, "HL7 message parsed successfully", message, http.StatusOK)
+}
 
-	for _, segment := range m.Segments {
-		messageBuilder.WriteString(segment.Type)
-		for _, field := range segment.Fields {
-			messageBuilder.WriteString(FieldSeparator)
-			messageBuilder.WriteString(field)
-		}
-		messageBuilder.WriteString(SegmentSeparator)
+// handleGenerateHL7 handles GET requests to generate sample HL7 messages
+func handleGenerateHL7(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendJSONResponse(w, false, "Method not allowed", nil, http.StatusMethodNotAllowed)
+		return
 	}
 
-	return messageBuilder.String()
+	message := GenerateSampleMessage()
+	sendJSONResponse(w, true, "HL7 message generated successfully", message, http.StatusOK)
 }
 
-// ParseHL7Message parses an HL7 message string into an HL7Message struct
-func ParseHL7Message(messageStr string) (*HL7Message, error) {
-	message := NewHL7Message()
+// handleConvertFHIR converts between HL7 v2 and FHIR R4. A request with
+// a JSON body is treated as a FHIR message Bundle and converted to HL7
+// v2; anything else is treated as a raw HL7 v2 message and converted to
+// a FHIR Bundle.
+func handleConvertFHIR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	scanner := bufio.NewScanner(strings.NewReader(messageStr))
-	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		if atEOF && len(data) == 0 {
-			return 0, nil, nil
-		}
-		if i := strings.Index(string(data), SegmentSeparator); i >= 0 {
-			return i + 1, data[0:i], nil
-		}
-		if atEOF {
-			return len(data), data, nil
-		}
-		return 0, nil, nil
-	})
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error reading request body: %v", err), http.StatusBadRequest)
+		return
+	}
 
-	for scanner.Scan() {
-		segmentStr := scanner.Text()
-		if len(segmentStr) == 0 {
-			continue
+	if strings.Contains(r.Header.Get("Content-Type"), "json") {
+		var bundle fhir.Bundle
+		if err := json.Unmarshal(body, &bundle); err != nil {
+			http.Error(w, fmt.Sprintf("Error decoding FHIR bundle: %v", err), http.StatusBadRequest)
+			return
 		}
 
-		fields := strings.Split(segmentStr, FieldSeparator)
-		if len(fields) < 1 {
-			return nil, errors.New("invalid segment format")
+		message, err := fhir.FromFHIRBundle(&bundle)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error converting FHIR bundle: %v", err), http.StatusUnprocessableEntity)
+			return
 		}
 
-		segment := Segment{
-			Type:   fields[0],
-			Fields: fields[1:],
-		}
-		message.Segments = append(message.Segments, segment)
+		w.Header().Set("Content-Type", "x-application/hl7-v2+er7")
+		w.Write([]byte(message.GenerateMessage()))
+		return
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	message, err := hl7.ParseHL7Message(string(body))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing HL7 message: %v", err), http.StatusBadRequest)
+		return
 	}
 
-	return message, nil
-}
+	bundle, err := fhir.ToFHIRBundle(message)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error converting to FHIR: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
 
-// HTTP Server handlers
+	w.Header().Set("Content-Type", "application/fhir+json")
+	json.NewEncoder(w).Encode(bundle)
+}
 
-// handleParseHL7 handles POST requests to parse HL7 messages
-func handleParseHL7(w http.ResponseWriter, r *http.Request) {
+// handleValidate validates a posted HL7 v2 message against the
+// built-in profile for its message type (MSH-9) and version (MSH-12),
+// returning a structured list of issues rather than a single error.
+func handleValidate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		sendJSONResponse(w, false, "Method not allowed", nil, http.StatusMethodNotAllowed)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		sendJSONResponse(w, false, "Error reading request body", nil, http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Error reading request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	message, err := ParseHL7Message(string(body))
+	message, err := hl7.ParseHL7Message(string(body))
 	if err != nil {
-		sendJSONResponse(w, false, fmt.Sprintf("Error parsing HL7 message: %v", err), nil, http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Error parsing HL7 message: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	sendJSONResponse(w, true, "HL7 message parsed successfully", message, http.StatusOK)
+	msh, ok := message.MSH()
+	if !ok {
+		http.Error(w, "Message has no MSH segment", http.StatusUnprocessableEntity)
+		return
+	}
+
+	messageType := msh.MessageType() + "^" + msh.TriggerEvent()
+	profile, ok := validation.BuiltinProfile(messageType, msh.VersionID())
+	if !ok {
+		http.Error(w, fmt.Sprintf("No profile registered for %s at version %s", messageType, msh.VersionID()), http.StatusUnprocessableEntity)
+		return
+	}
+
+	issues := validation.Validate(message, profile)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(issues)
 }
 
-// handleGenerateHL7 handles GET requests to generate sample HL7 messages
-func handleGenerateHL7(w http.ResponseWriter, r *http.Request) {
+// persistMiddleware saves every message the server handles to s before
+// passing it on, turning the middleware chain into an audit trail.
+func persistMiddleware(s store.Store) hl7d.Middleware {
+	return func(next hl7d.Handler) hl7d.Handler {
+		return func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+			if _, err := s.Save(msg); err != nil {
+				log.Printf("store: failed to save message: %v", err)
+			}
+			return next(ctx, msg)
+		}
+	}
+}
+
+// handleMessages serves the message store's query/fetch/replay API:
+//
+//	GET  /messages?type=ADT^A01&sendingApp=FOO&since=2024-01-01T00:00:00Z&limit=50&offset=100
+//	GET  /messages/{id}
+//	POST /messages/{id}/replay?to=mllp://host:port
+func handleMessages(s store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/messages"), "/")
+
+		switch {
+		case path == "":
+			handleQueryMessages(s, w, r)
+		case strings.HasSuffix(path, "/replay"):
+			id := strings.TrimPrefix(strings.TrimSuffix(path, "/replay"), "/")
+			handleReplayMessage(s, w, r, id)
+		default:
+			id := strings.TrimPrefix(path, "/")
+			handleGetMessage(s, w, r, id)
+		}
+	}
+}
+
+func handleQueryMessages(s store.Store, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		sendJSONResponse(w, false, "Method not allowed", nil, http.StatusMethodNotAllowed)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	message := GenerateSampleMessage()
-	sendJSONResponse(w, true, "HL7 message generated successfully", message, http.StatusOK)
+	filter := store.Filter{
+		MessageType:        r.URL.Query().Get("type"),
+		SendingApplication: r.URL.Query().Get("sendingApp"),
+	}
+	if since := r.URL.Query().Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid since: %v", err), http.StatusBadRequest)
+			return
+		}
+		filter.Since = t
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid limit: %v", err), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid offset: %v", err), http.StatusBadRequest)
+			return
+		}
+		filter.Offset = n
+	}
+
+	records, err := s.Query(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+func handleGetMessage(s store.Store, w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	message, err := s.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "x-application/hl7-v2+er7")
+	w.Write([]byte(message.GenerateMessage()))
+}
+
+func handleReplayMessage(s store.Store, w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dest := r.URL.Query().Get("to")
+	if dest == "" {
+		http.Error(w, "Missing required query parameter: to", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Replay(id, dest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	sendJSONResponse(w, true, fmt.Sprintf("Message %s replayed to %s", id, dest), nil, http.StatusOK)
 }
 
 // Helper function to send JSON responses
-func sendJSONResponse(w http.ResponseWriter, success bool, message string, data *HL7Message, statusCode int) {
+func sendJSONResponse(w http.ResponseWriter, success bool, message string, data *hl7.HL7Message, statusCode int) {
 	response := HTTPResponse{
 		Success: success,
 		Message: message,
@@ -172,8 +289,8 @@ func sendJSONResponse(w http.ResponseWriter, success bool, message string, data
 }
 
 // Helper function to generate a sample HL7 message
-func GenerateSampleMessage() *HL7Message {
-	message := NewHL7Message()
+func GenerateSampleMessage() *hl7.HL7Message {
+	message := hl7.NewHL7Message()
 
 	currentTime := time.Now().Format("20060102150405")
 	message.AddSegment("MSH",
@@ -205,7 +322,7 @@ func GenerateSampleMessage() *HL7Message {
 // HTTP Client functions
 
 // SendHL7Message sends an HL7 message to a server
-func SendHL7Message(url string, message *HL7Message) (*HTTPResponse, error) {
+func SendHL7Message(url string, message *hl7.HL7Message) (*HTTPResponse, error) {
 	hl7String := message.GenerateMessage()
 
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(hl7String))
@@ -262,6 +379,25 @@ func handleVersion(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(info)
 }
 
+// newServer builds the shared hl7d.Server: a logging/recovery
+// middleware chain around a Router that accepts any ADT or ORU message
+// type and acknowledges it. The HTTP, MLLP, and file-drop transports
+// all dispatch through this same Server, so a handler registered once
+// behaves identically regardless of how the message arrived.
+func newServer() *hl7d.Server {
+	router := hl7d.NewRouter()
+
+	accept := func(ctx context.Context, msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+		return mllp.GenerateACK(msg, "AA", ""), nil
+	}
+	router.Handle("ADT^*", accept)
+	router.Handle("ORU^*", accept)
+
+	server := hl7d.NewServer(router.ServeHL7)
+	server.Use(hl7d.Recover(), hl7d.Logging(log.Default()))
+	return server
+}
+
 func main() {
 	// Print version information
 	fmt.Printf("HL7 Processor v%s (Built: %s)\n", Version, BuildDate)
@@ -271,18 +407,65 @@ func main() {
 	if port == "" {
 		port = "8080"
 	}
+
+	server := newServer()
+
 	// Set up HTTP server routes
 	http.HandleFunc("/parse", handleParseHL7)
 	http.HandleFunc("/generate", handleGenerateHL7)
+	http.HandleFunc("/convert/fhir", handleConvertFHIR)
+	http.HandleFunc("/validate", handleValidate)
+	http.HandleFunc("/hl7", hl7d.HTTPHandler(server))
 	http.HandleFunc("/health", handleHealth)
 	http.HandleFunc("/version", handleVersion)
 
+	// Optionally persist every message the server handles and expose the
+	// query/fetch/replay API over it
+	if storePath := os.Getenv("HL7_STORE_PATH"); storePath != "" {
+		messageStore, err := store.Open(storePath)
+		if err != nil {
+			log.Fatalf("Error opening message store: %v", err)
+		}
+
+		server.Use(persistMiddleware(messageStore))
+		http.HandleFunc("/messages", handleMessages(messageStore))
+		http.HandleFunc("/messages/", handleMessages(messageStore))
+	}
+
+	// Optionally fan every handled message out to downstream systems
+	if destinations := os.Getenv("HL7_FORWARD_DESTINATIONS"); destinations != "" {
+		server.Use(hl7d.Forwarder(hl7d.ForwarderConfig{
+			Destinations:  strings.Split(destinations, ","),
+			DeadLetterDir: os.Getenv("HL7_FORWARD_DEADLETTER_DIR"),
+		}))
+	}
+
 	// Server example
 	go func() {
 		fmt.Println("Starting server on :8080...")
 		log.Fatal(http.ListenAndServe(":8080", nil))
 	}()
 
+	// Optionally accept HL7 over MLLP on a raw TCP socket alongside HTTP
+	if mllpPort := os.Getenv("HL7_MLLP_PORT"); mllpPort != "" {
+		mllpServer := mllp.NewServer(":"+mllpPort, hl7d.MLLPHandler(server))
+		go func() {
+			fmt.Printf("Starting MLLP listener on :%s...\n", mllpPort)
+			log.Fatal(mllpServer.ListenAndServe())
+		}()
+	}
+
+	// Optionally ingest HL7 messages dropped as files into a directory
+	if watchDir := os.Getenv("HL7_FILEWATCH_DIR"); watchDir != "" {
+		watcher := &hl7d.FileWatcher{Dir: watchDir, Server: server}
+		go func() {
+			fmt.Printf("Watching %s for HL7 files...\n", watchDir)
+			if err := watcher.Run(context.Background()); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	// Keep the main thread running
 	select {}
 }
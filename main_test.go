@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+	"github.com/sodrety/hl7-middleware/store"
+)
+
+// fakeStore is a minimal store.Store that records the Filter it was
+// queried with, so handlers can be tested without a real database.
+type fakeStore struct {
+	lastFilter store.Filter
+}
+
+func (f *fakeStore) Save(msg *hl7.HL7Message) (string, error) { return "1", nil }
+func (f *fakeStore) Get(id string) (*hl7.HL7Message, error)   { return hl7.NewHL7Message(), nil }
+func (f *fakeStore) Query(filter store.Filter) ([]store.Record, error) {
+	f.lastFilter = filter
+	return nil, nil
+}
+func (f *fakeStore) Replay(id string, dest string) error { return nil }
+func (f *fakeStore) Close() error                        { return nil }
+
+func TestHandleQueryMessagesParsesLimitAndOffset(t *testing.T) {
+	fs := &fakeStore{}
+	req := httptest.NewRequest("GET", "/messages?type=ADT^A01&limit=25&offset=50", nil)
+	w := httptest.NewRecorder()
+
+	handleQueryMessages(fs, w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if fs.lastFilter.Limit != 25 || fs.lastFilter.Offset != 50 {
+		t.Fatalf("filter = %+v, want Limit=25 Offset=50", fs.lastFilter)
+	}
+	if fs.lastFilter.MessageType != "ADT^A01" {
+		t.Fatalf("filter.MessageType = %q, want ADT^A01", fs.lastFilter.MessageType)
+	}
+}
+
+func TestHandleQueryMessagesRejectsBadLimit(t *testing.T) {
+	fs := &fakeStore{}
+	req := httptest.NewRequest("GET", "/messages?limit=notanumber", nil)
+	w := httptest.NewRecorder()
+
+	handleQueryMessages(fs, w, req)
+
+	if w.Code != 400 {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+}
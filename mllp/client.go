@@ -0,0 +1,74 @@
+package mllp
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// Client is a persistent MLLP connection to a remote listener. A single
+// Client can send many messages over the same TCP session, mirroring
+// how real HL7 sending systems behave.
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	Timeout time.Duration
+}
+
+// Dial opens a plain TCP MLLP connection to addr.
+func Dial(addr string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("mllp: dial %s: %w", addr, err)
+	}
+	return newClient(conn, timeout), nil
+}
+
+// DialTLS opens a TLS MLLP connection to addr.
+func DialTLS(addr string, tlsConfig *tls.Config, timeout time.Duration) (*Client, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("mllp: dial tls %s: %w", addr, err)
+	}
+	return newClient(conn, timeout), nil
+}
+
+func newClient(conn net.Conn, timeout time.Duration) *Client {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	scanner.Split(splitFrame)
+	return &Client{conn: conn, scanner: scanner, Timeout: timeout}
+}
+
+// Send frames and writes msg, then blocks until the framed ACK/NAK
+// arrives or c.Timeout elapses.
+func (c *Client) Send(msg *hl7.HL7Message) (*hl7.HL7Message, error) {
+	if c.Timeout > 0 {
+		if err := c.conn.SetDeadline(time.Now().Add(c.Timeout)); err != nil {
+			return nil, fmt.Errorf("mllp: set deadline: %w", err)
+		}
+	}
+
+	if _, err := c.conn.Write(Frame(msg.GenerateMessage())); err != nil {
+		return nil, fmt.Errorf("mllp: write: %w", err)
+	}
+
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return nil, fmt.Errorf("mllp: read ack: %w", err)
+		}
+		return nil, fmt.Errorf("mllp: connection closed before ack")
+	}
+
+	return hl7.ParseHL7Message(string(c.scanner.Bytes()))
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
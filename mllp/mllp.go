@@ -0,0 +1,202 @@
+// Package mllp implements the Minimum Lower Layer Protocol used to
+// exchange HL7 v2 messages over raw TCP, as an alternative to the HTTP
+// transport. Each message is framed as:
+//
+//	<VT> message <FS><CR>
+//
+// where <VT> is 0x0B and <FS><CR> is 0x1C 0x0D.
+package mllp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+const (
+	// StartBlock marks the beginning of an MLLP frame.
+	StartBlock = 0x0B
+	// EndBlock and CarriageReturn together mark the end of an MLLP frame.
+	EndBlock       = 0x1C
+	CarriageReturn = 0x0D
+)
+
+// maxMessageSize bounds how large a single framed message may be, so a
+// misbehaving peer that never sends an end block can't grow the scan
+// buffer without limit.
+const maxMessageSize = 10 * 1024 * 1024
+
+// Handler processes a received HL7 message and returns the ACK/NAK
+// message to send back to the caller.
+type Handler func(msg *hl7.HL7Message) *hl7.HL7Message
+
+// Server listens for MLLP connections and dispatches framed messages to
+// a Handler.
+type Server struct {
+	// Addr is the TCP address to listen on, e.g. ":2575".
+	Addr string
+	// TLSConfig, if non-nil, makes the server accept TLS connections
+	// instead of plain TCP.
+	TLSConfig *tls.Config
+	// Handler is invoked once per received message.
+	Handler Handler
+
+	listener net.Listener
+}
+
+// NewServer creates an MLLP server that will invoke handler for every
+// message it receives.
+func NewServer(addr string, handler Handler) *Server {
+	return &Server{Addr: addr, Handler: handler}
+}
+
+// ListenAndServe starts accepting connections and blocks until the
+// listener is closed or an unrecoverable error occurs.
+func (s *Server) ListenAndServe() error {
+	var ln net.Listener
+	var err error
+	if s.TLSConfig != nil {
+		ln, err = tls.Listen("tcp", s.Addr, s.TLSConfig)
+	} else {
+		ln, err = net.Listen("tcp", s.Addr)
+	}
+	if err != nil {
+		return fmt.Errorf("mllp: listen on %s: %w", s.Addr, err)
+	}
+	s.listener = ln
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops the server from accepting new connections.
+func (s *Server) Close() error {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+// serveConn reads framed messages from a single connection until the
+// peer disconnects, so one TCP session can carry many messages.
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	scanner.Split(splitFrame)
+
+	for scanner.Scan() {
+		msg, err := hl7.ParseHL7Message(string(scanner.Bytes()))
+		if err != nil {
+			continue
+		}
+
+		var ack *hl7.HL7Message
+		if s.Handler != nil {
+			ack = s.Handler(msg)
+		}
+		if ack == nil {
+			ack = GenerateACK(msg, "AA", "")
+		}
+
+		if _, err := conn.Write(Frame(ack.GenerateMessage())); err != nil {
+			return
+		}
+	}
+}
+
+// splitFrame is a bufio.SplitFunc that finds one MLLP-framed message at
+// a time, stripping the surrounding <VT> ... <FS><CR> markers.
+func splitFrame(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	start := bytes.IndexByte(data, StartBlock)
+	if start < 0 {
+		// Discard noise before the first start block.
+		if atEOF {
+			return len(data), nil, nil
+		}
+		return 0, nil, nil
+	}
+
+	end := bytes.Index(data[start+1:], []byte{EndBlock, CarriageReturn})
+	if end < 0 {
+		if atEOF {
+			return 0, nil, fmt.Errorf("mllp: truncated frame, missing end block")
+		}
+		return 0, nil, nil
+	}
+
+	msgStart := start + 1
+	msgEnd := msgStart + end
+	return msgEnd + 2, data[msgStart:msgEnd], nil
+}
+
+// Frame wraps an HL7 message string in MLLP start/end blocks.
+func Frame(message string) []byte {
+	buf := make([]byte, 0, len(message)+3)
+	buf = append(buf, StartBlock)
+	buf = append(buf, message...)
+	buf = append(buf, EndBlock, CarriageReturn)
+	return buf
+}
+
+// GenerateACK builds an ACK/NAK message for msg, copying the sending
+// MSH-10 control ID into MSA-2. ackCode is typically "AA" (accept),
+// "AE" (error), or "AR" (reject). If detail is non-empty it is carried
+// as MSA-3.
+func GenerateACK(msg *hl7.HL7Message, ackCode, detail string) *hl7.HL7Message {
+	ack := hl7.NewHL7Message()
+
+	encodingChars, version := "^~\\&", "2.5"
+	var controlID, sendingApp, sendingFacility, receivingApp, receivingFacility string
+
+	if msh, ok := msg.MSH(); ok {
+		if v, ok := msh.Get(2, 1, 1, 0); ok {
+			encodingChars = v
+		}
+		sendingApp = msh.SendingApplication()
+		sendingFacility = msh.SendingFacility()
+		receivingApp = msh.ReceivingApplication()
+		receivingFacility = msh.ReceivingFacility()
+		controlID = msh.MessageControlID()
+		if v := msh.VersionID(); v != "" {
+			version = v
+		}
+	}
+
+	ack.AddSegment("MSH",
+		encodingChars,
+		receivingApp,
+		receivingFacility,
+		sendingApp,
+		sendingFacility,
+		time.Now().Format("20060102150405"),
+		"",
+		"ACK",
+		controlID+"-ACK",
+		"P",
+		version,
+	)
+
+	if detail != "" {
+		ack.AddSegment("MSA", ackCode, controlID, detail)
+	} else {
+		ack.AddSegment("MSA", ackCode, controlID)
+	}
+
+	return ack
+}
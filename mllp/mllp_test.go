@@ -0,0 +1,79 @@
+package mllp
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	framed := Frame("MSH|^~\\&|A|B\r")
+	if framed[0] != StartBlock {
+		t.Fatalf("frame does not start with StartBlock")
+	}
+	if framed[len(framed)-2] != EndBlock || framed[len(framed)-1] != CarriageReturn {
+		t.Fatalf("frame does not end with EndBlock/CarriageReturn")
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(framed))
+	scanner.Split(splitFrame)
+	if !scanner.Scan() {
+		t.Fatalf("splitFrame did not find the message: %v", scanner.Err())
+	}
+	if got := string(scanner.Bytes()); got != "MSH|^~\\&|A|B\r" {
+		t.Fatalf("splitFrame = %q, want %q", got, "MSH|^~\\&|A|B\r")
+	}
+}
+
+func TestSplitFrameMultipleMessagesOnOneConnection(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(Frame("MSG1"))
+	buf.Write(Frame("MSG2"))
+
+	scanner := bufio.NewScanner(&buf)
+	scanner.Split(splitFrame)
+
+	var got []string
+	for scanner.Scan() {
+		got = append(got, string(scanner.Bytes()))
+	}
+	if len(got) != 2 || got[0] != "MSG1" || got[1] != "MSG2" {
+		t.Fatalf("got %v, want [MSG1 MSG2]", got)
+	}
+}
+
+func TestGenerateACKCopiesControlIDAndEncodingChars(t *testing.T) {
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|SEND|FAC|RECV|RECV_FAC|20240101120000||ADT^A01|MSG001|P|2.5\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	ack := GenerateACK(msg, "AA", "")
+	ackMSH, ok := ack.MSH()
+	if !ok {
+		t.Fatalf("ack has no MSH segment")
+	}
+
+	if v, _ := ackMSH.Get(2, 1, 1, 0); v != "^~\\&" {
+		t.Errorf("ack MSH-2 = %q, want %q", v, "^~\\&")
+	}
+	if ackMSH.SendingApplication() != "RECV" {
+		t.Errorf("ack sending application = %q, want RECV (the original receiver)", ackMSH.SendingApplication())
+	}
+	if ackMSH.ReceivingApplication() != "SEND" {
+		t.Errorf("ack receiving application = %q, want SEND (the original sender)", ackMSH.ReceivingApplication())
+	}
+
+	msa, ok := ack.FirstSegment("MSA")
+	if !ok {
+		t.Fatalf("ack has no MSA segment")
+	}
+	if code, _ := msa.Get(1, 1, 1, 0); code != "AA" {
+		t.Errorf("MSA-1 = %q, want AA", code)
+	}
+	if controlID, _ := msa.Get(2, 1, 1, 0); controlID != "MSG001" {
+		t.Errorf("MSA-2 = %q, want MSG001", controlID)
+	}
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/sodrety/hl7-middleware/dispatch"
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS messages (
+	id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+	message_type         TEXT,
+	message_control_id   TEXT,
+	sending_application  TEXT,
+	sending_facility     TEXT,
+	patient_id           TEXT,
+	message_timestamp    TEXT,
+	received_at          DATETIME NOT NULL,
+	raw                  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_messages_type ON messages(message_type);
+CREATE INDEX IF NOT EXISTS idx_messages_sending_app ON messages(sending_application);
+`
+
+// SQLiteStore is the default Store implementation, backed by a SQLite
+// database file.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// Open creates (or reopens) a SQLite-backed Store at path.
+func Open(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: create schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Save implements Store.
+func (s *SQLiteStore) Save(msg *hl7.HL7Message) (string, error) {
+	record := recordFromMessage("", msg, time.Now())
+
+	res, err := s.db.Exec(
+		`INSERT INTO messages (message_type, message_control_id, sending_application, sending_facility, patient_id, message_timestamp, received_at, raw)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.MessageType, record.MessageControlID, record.SendingApplication, record.SendingFacility,
+		record.PatientID, record.MessageTimestamp, record.ReceivedAt, record.Raw,
+	)
+	if err != nil {
+		return "", fmt.Errorf("store: save message: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return "", fmt.Errorf("store: read inserted id: %w", err)
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// Get implements Store.
+func (s *SQLiteStore) Get(id string) (*hl7.HL7Message, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT raw FROM messages WHERE id = ?`, id).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("store: message %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("store: get message %s: %w", id, err)
+	}
+	return hl7.ParseHL7Message(raw)
+}
+
+// Query implements Store.
+func (s *SQLiteStore) Query(filter Filter) ([]Record, error) {
+	query := strings.Builder{}
+	query.WriteString(`SELECT id, message_type, message_control_id, sending_application, sending_facility, patient_id, message_timestamp, received_at, raw FROM messages WHERE 1=1`)
+
+	var args []interface{}
+	if filter.MessageType != "" {
+		query.WriteString(" AND message_type = ?")
+		args = append(args, filter.MessageType)
+	}
+	if filter.SendingApplication != "" {
+		query.WriteString(" AND sending_application = ?")
+		args = append(args, filter.SendingApplication)
+	}
+	if !filter.Since.IsZero() {
+		query.WriteString(" AND received_at >= ?")
+		args = append(args, filter.Since)
+	}
+
+	query.WriteString(" ORDER BY received_at DESC")
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query.WriteString(" LIMIT ?")
+	args = append(args, limit)
+	if filter.Offset > 0 {
+		query.WriteString(" OFFSET ?")
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := s.db.Query(query.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("store: query messages: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		var id int64
+		if err := rows.Scan(&id, &r.MessageType, &r.MessageControlID, &r.SendingApplication, &r.SendingFacility,
+			&r.PatientID, &r.MessageTimestamp, &r.ReceivedAt, &r.Raw); err != nil {
+			return nil, fmt.Errorf("store: scan message row: %w", err)
+		}
+		r.ID = strconv.FormatInt(id, 10)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// Replay implements Store.
+func (s *SQLiteStore) Replay(id string, dest string) error {
+	msg, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	return dispatch.Send(dest, msg)
+}
+
+// Close implements Store.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+func openTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "messages.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func mustParse(t *testing.T, raw string) *hl7.HL7Message {
+	t.Helper()
+	msg, err := hl7.ParseHL7Message(raw)
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+	return msg
+}
+
+func TestSaveAndGetRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+	msg := mustParse(t, "MSH|^~\\&|SEND|FAC|||||ADT^A01|MSG1|P|2.5\rPID|||12345\r")
+
+	id, err := s.Save(msg)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := s.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	gotMSH, _ := got.MSH()
+	if gotMSH.MessageControlID() != "MSG1" {
+		t.Errorf("roundtripped MSH-10 = %q, want MSG1", gotMSH.MessageControlID())
+	}
+}
+
+func TestQueryFiltersByMessageType(t *testing.T) {
+	s := openTestStore(t)
+	if _, err := s.Save(mustParse(t, "MSH|^~\\&|SEND|FAC|||||ADT^A01|MSG1|P|2.5\r")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := s.Save(mustParse(t, "MSH|^~\\&|SEND|FAC|||||ORU^R01|MSG2|P|2.5\r")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	records, err := s.Query(Filter{MessageType: "ADT^A01"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(records) != 1 || records[0].MessageControlID != "MSG1" {
+		t.Fatalf("records = %+v, want exactly MSG1", records)
+	}
+}
+
+func TestQueryPagination(t *testing.T) {
+	s := openTestStore(t)
+	for i := 0; i < 5; i++ {
+		if _, err := s.Save(mustParse(t, "MSH|^~\\&|SEND|FAC|||||ADT^A01|MSG|P|2.5\r")); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	page1, err := s.Query(Filter{Limit: 2, Offset: 0})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	page2, err := s.Query(Filter{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(page1) != 2 || len(page2) != 2 {
+		t.Fatalf("page1=%d page2=%d records, want 2 and 2", len(page1), len(page2))
+	}
+	if page1[0].ID == page2[0].ID {
+		t.Fatalf("page1 and page2 overlap at %s; offset was not applied", page1[0].ID)
+	}
+}
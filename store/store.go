@@ -0,0 +1,65 @@
+// Package store persists HL7 v2 messages so a middleware deployment can
+// be queried and messages replayed after the fact, rather than acting
+// as a stateless translator.
+package store
+
+import (
+	"time"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// Record is one stored message plus the metadata extracted from it at
+// save time, so it can be queried without reparsing the raw text.
+type Record struct {
+	ID                 string
+	MessageType        string // MSH-9, e.g. "ADT^A01"
+	MessageControlID   string
+	SendingApplication string
+	SendingFacility    string
+	PatientID          string // PID-3
+	MessageTimestamp   string // MSH-7, as sent
+	ReceivedAt         time.Time
+	Raw                string
+}
+
+// Filter selects which Records Query returns.
+type Filter struct {
+	MessageType        string
+	SendingApplication string
+	Since              time.Time
+	Limit              int
+	Offset             int
+}
+
+// Store persists and retrieves HL7 messages.
+type Store interface {
+	// Save persists msg and returns the ID it was stored under.
+	Save(msg *hl7.HL7Message) (id string, err error)
+	// Get retrieves the raw message previously stored under id.
+	Get(id string) (*hl7.HL7Message, error)
+	// Query returns the Records matching filter, most recent first.
+	Query(filter Filter) ([]Record, error)
+	// Replay re-sends the message stored under id to dest, which is
+	// either "http://host/path" or "mllp://host:port".
+	Replay(id string, dest string) error
+	// Close releases any underlying resources.
+	Close() error
+}
+
+func recordFromMessage(id string, msg *hl7.HL7Message, receivedAt time.Time) Record {
+	record := Record{ID: id, Raw: msg.GenerateMessage(), ReceivedAt: receivedAt}
+
+	if msh, ok := msg.MSH(); ok {
+		record.MessageType = msh.MessageType() + "^" + msh.TriggerEvent()
+		record.MessageControlID = msh.MessageControlID()
+		record.SendingApplication = msh.SendingApplication()
+		record.SendingFacility = msh.SendingFacility()
+		record.MessageTimestamp = msh.DateTimeOfMessage()
+	}
+	if pid, ok := msg.PID(); ok {
+		record.PatientID = pid.PatientID()
+	}
+
+	return record
+}
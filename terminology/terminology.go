@@ -0,0 +1,60 @@
+// Package terminology maps HL7 v2 coded values to their FHIR
+// equivalents. The built-in Mapper covers the tables used by the
+// fhir package's default conversion; callers that need site-specific
+// codes can implement Mapper themselves and assign it in place of the
+// default.
+package terminology
+
+// Mapper translates HL7 v2 table values into FHIR codes.
+type Mapper interface {
+	// AdministrativeGender maps an HL7 v2 table 0001 (Sex) value to a
+	// FHIR AdministrativeGender code.
+	AdministrativeGender(v2Code string) string
+	// MaritalStatus maps an HL7 v2 table 0002 (Marital Status) value to
+	// a FHIR marital-status code.
+	MaritalStatus(v2Code string) string
+}
+
+// Default returns the built-in Mapper for HL7 v2 table 0001 (Sex) and
+// table 0002 (Marital Status).
+func Default() Mapper {
+	return defaultMapper{}
+}
+
+type defaultMapper struct{}
+
+// v2 table 0001 (Sex) -> FHIR AdministrativeGender
+// https://hl7.org/fhir/valueset-administrative-gender.html
+var administrativeGender = map[string]string{
+	"M": "male",
+	"F": "female",
+	"O": "other",
+	"U": "unknown",
+	"A": "other",
+	"N": "other",
+}
+
+func (defaultMapper) AdministrativeGender(v2Code string) string {
+	if v, ok := administrativeGender[v2Code]; ok {
+		return v
+	}
+	return "unknown"
+}
+
+// v2 table 0002 (Marital Status) -> FHIR marital-status
+// https://hl7.org/fhir/v3/MaritalStatus/vs.html
+var maritalStatus = map[string]string{
+	"A": "U", // separated -> unmarried (closest v3 equivalent used by FHIR)
+	"D": "D", // divorced
+	"M": "M", // married
+	"S": "S", // never married
+	"W": "W", // widowed
+	"C": "L", // common law -> legally separated (closest approximation)
+}
+
+func (defaultMapper) MaritalStatus(v2Code string) string {
+	if v, ok := maritalStatus[v2Code]; ok {
+		return v
+	}
+	return "UNK"
+}
@@ -0,0 +1,101 @@
+package validation
+
+// builtinProfiles holds the out-of-the-box profiles for the most
+// common trigger events at HL7 v2.5, keyed by "<messageType>|<version>".
+var builtinProfiles = map[string]*Profile{}
+
+func register(p *Profile) {
+	builtinProfiles[p.MessageType+"|"+p.Version] = p
+}
+
+// BuiltinProfile returns the built-in profile for messageType (e.g.
+// "ADT^A01") and version (e.g. "2.5"), if one is registered.
+func BuiltinProfile(messageType, version string) (*Profile, bool) {
+	p, ok := builtinProfiles[messageType+"|"+version]
+	return p, ok
+}
+
+func init() {
+	mshFields := []FieldDef{
+		{Index: 9, Name: "Message Type", Required: true, DataType: "CE"},
+		{Index: 10, Name: "Message Control ID", Required: true, DataType: "ST", MaxLength: 20},
+		{Index: 12, Name: "Version ID", Required: true, DataType: "ID"},
+	}
+	pidFields := []FieldDef{
+		{Index: 3, Name: "Patient Identifier List", Required: true, DataType: "CX"},
+		{Index: 5, Name: "Patient Name", Required: true, DataType: "XPN"},
+		{Index: 7, Name: "Date/Time of Birth", DataType: "TS"},
+		{Index: 8, Name: "Administrative Sex", DataType: "IS"},
+	}
+
+	register(&Profile{
+		MessageType: "ADT^A01",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "MSH", Required: true, MaxOccurs: 1, Fields: mshFields},
+			{Name: "EVN", Required: true, MaxOccurs: 1},
+			{Name: "PID", Required: true, MaxOccurs: 1, Fields: pidFields},
+			{Name: "PV1", Required: true, MaxOccurs: 1, Fields: []FieldDef{
+				{Index: 2, Name: "Patient Class", Required: true, DataType: "IS"},
+			}},
+		},
+	})
+
+	register(&Profile{
+		MessageType: "ORU^R01",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "MSH", Required: true, MaxOccurs: 1, Fields: mshFields},
+			{Name: "PID", Required: true, MaxOccurs: 1, Fields: pidFields},
+			{Name: "OBR", Required: true, Fields: []FieldDef{
+				{Index: 4, Name: "Universal Service Identifier", Required: true, DataType: "CE"},
+			}},
+			{Name: "OBX", Required: true, Fields: []FieldDef{
+				{Index: 2, Name: "Value Type", DataType: "ID"},
+				{Index: 3, Name: "Observation Identifier", Required: true, DataType: "CE"},
+				{Index: 11, Name: "Observation Result Status", Required: true, DataType: "ID"},
+			}},
+		},
+	})
+
+	register(&Profile{
+		MessageType: "ORM^O01",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "MSH", Required: true, MaxOccurs: 1, Fields: mshFields},
+			{Name: "PID", Required: true, MaxOccurs: 1, Fields: pidFields},
+			{Name: "ORC", Required: true, Fields: []FieldDef{
+				{Index: 1, Name: "Order Control", Required: true, DataType: "ID"},
+			}},
+			{Name: "OBR", Required: true, Fields: []FieldDef{
+				{Index: 4, Name: "Universal Service Identifier", Required: true, DataType: "CE"},
+			}},
+		},
+	})
+
+	register(&Profile{
+		MessageType: "SIU^S12",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "MSH", Required: true, MaxOccurs: 1, Fields: mshFields},
+			{Name: "SCH", Required: true, MaxOccurs: 1, Fields: []FieldDef{
+				{Index: 1, Name: "Placer Appointment ID", DataType: "EI"},
+				{Index: 11, Name: "Appointment Timing Quantity", Required: true, DataType: "TQ"},
+			}},
+			{Name: "PID", Required: true, MaxOccurs: 1, Fields: pidFields},
+		},
+	})
+
+	register(&Profile{
+		MessageType: "MDM^T02",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "MSH", Required: true, MaxOccurs: 1, Fields: mshFields},
+			{Name: "EVN", Required: true, MaxOccurs: 1},
+			{Name: "PID", Required: true, MaxOccurs: 1, Fields: pidFields},
+			{Name: "TXA", Required: true, MaxOccurs: 1, Fields: []FieldDef{
+				{Index: 2, Name: "Document Type", Required: true, DataType: "IS"},
+			}},
+		},
+	})
+}
@@ -0,0 +1,66 @@
+// Package validation checks parsed HL7 v2 messages against a
+// conformance profile describing which segments and fields a given
+// message type (e.g. "ADT^A01") is expected to carry.
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile describes the expected shape of one message type/version
+// combination, e.g. "ADT^A01" at version "2.5".
+type Profile struct {
+	MessageType string       `json:"messageType" yaml:"messageType"`
+	Version     string       `json:"version" yaml:"version"`
+	Segments    []SegmentDef `json:"segments" yaml:"segments"`
+}
+
+// SegmentDef describes one expected segment and its cardinality.
+type SegmentDef struct {
+	Name string `json:"name" yaml:"name"`
+	// Required segments must appear at least once.
+	Required bool `json:"required" yaml:"required"`
+	// MaxOccurs is the maximum number of times the segment may repeat;
+	// 0 means unbounded.
+	MaxOccurs int        `json:"maxOccurs,omitempty" yaml:"maxOccurs,omitempty"`
+	Fields    []FieldDef `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// FieldDef describes one expected field within a segment.
+type FieldDef struct {
+	Index     int    `json:"index" yaml:"index"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Required  bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	DataType  string `json:"dataType,omitempty" yaml:"dataType,omitempty"`
+	MaxLength int    `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+}
+
+// LoadProfileJSON reads a Profile from a JSON file.
+func LoadProfileJSON(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("validation: read profile %s: %w", path, err)
+	}
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("validation: parse profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// LoadProfileYAML reads a Profile from a YAML file.
+func LoadProfileYAML(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("validation: read profile %s: %w", path, err)
+	}
+	var profile Profile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("validation: parse profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+// Severity classifies how serious an Issue is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Issue is one conformance problem found in a message.
+type Issue struct {
+	Segment    string   `json:"segment"`
+	FieldIndex int      `json:"fieldIndex,omitempty"`
+	Severity   Severity `json:"severity"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+}
+
+// Validator checks a message against a profile and reports any
+// conformance issues.
+type Validator interface {
+	Validate(msg *hl7.HL7Message, profile *Profile) []Issue
+}
+
+// Validate checks msg against profile using the DefaultValidator. It is
+// a convenience for callers that don't need a custom Validator, and is
+// the library entry point for validation: it cannot live as a
+// HL7Message.Validate method on the hl7 package, since Profile is
+// defined here in validation, which already imports hl7 - hl7 can't
+// import validation back without a cycle. Call this function, or use a
+// custom Validator directly, instead.
+func Validate(msg *hl7.HL7Message, profile *Profile) []Issue {
+	return DefaultValidator{}.Validate(msg, profile)
+}
+
+// DefaultValidator is the built-in Validator implementation, checking
+// segment cardinality, field presence, and field data types/lengths.
+type DefaultValidator struct{}
+
+// Validate checks msg's segments and fields against profile.
+func (DefaultValidator) Validate(msg *hl7.HL7Message, profile *Profile) []Issue {
+	var issues []Issue
+
+	for _, segDef := range profile.Segments {
+		segs := msg.SegmentsOfType(segDef.Name)
+
+		if segDef.Required && len(segs) == 0 {
+			issues = append(issues, Issue{
+				Segment:  segDef.Name,
+				Severity: SeverityError,
+				Code:     "SEGMENT_MISSING",
+				Message:  fmt.Sprintf("required segment %s is missing", segDef.Name),
+			})
+			continue
+		}
+		if segDef.MaxOccurs > 0 && len(segs) > segDef.MaxOccurs {
+			issues = append(issues, Issue{
+				Segment:  segDef.Name,
+				Severity: SeverityError,
+				Code:     "SEGMENT_CARDINALITY",
+				Message:  fmt.Sprintf("segment %s occurs %d times, maximum is %d", segDef.Name, len(segs), segDef.MaxOccurs),
+			})
+		}
+
+		for _, seg := range segs {
+			issues = append(issues, validateFields(seg, segDef)...)
+		}
+	}
+
+	return issues
+}
+
+func validateFields(seg *hl7.Segment, segDef SegmentDef) []Issue {
+	var issues []Issue
+
+	for _, fieldDef := range segDef.Fields {
+		value, present := seg.Get(fieldDef.Index, 1, 0, 0)
+
+		if fieldDef.Required && (!present || value == "") {
+			issues = append(issues, Issue{
+				Segment:    segDef.Name,
+				FieldIndex: fieldDef.Index,
+				Severity:   SeverityError,
+				Code:       "FIELD_MISSING",
+				Message:    fmt.Sprintf("required field %s-%d is missing", segDef.Name, fieldDef.Index),
+			})
+			continue
+		}
+		if !present || value == "" {
+			continue
+		}
+
+		if fieldDef.MaxLength > 0 && len(value) > fieldDef.MaxLength {
+			issues = append(issues, Issue{
+				Segment:    segDef.Name,
+				FieldIndex: fieldDef.Index,
+				Severity:   SeverityWarning,
+				Code:       "FIELD_TOO_LONG",
+				Message:    fmt.Sprintf("field %s-%d is %d characters, maximum is %d", segDef.Name, fieldDef.Index, len(value), fieldDef.MaxLength),
+			})
+		}
+
+		if fieldDef.DataType != "" && !validDataType(value, fieldDef.DataType) {
+			issues = append(issues, Issue{
+				Segment:    segDef.Name,
+				FieldIndex: fieldDef.Index,
+				Severity:   SeverityError,
+				Code:       "INVALID_DATA_TYPE",
+				Message:    fmt.Sprintf("field %s-%d value %q is not a valid %s", segDef.Name, fieldDef.Index, value, fieldDef.DataType),
+			})
+		}
+	}
+
+	return issues
+}
+
+// validDataType performs a light-weight shape check for the HL7 data
+// types used by the built-in profiles. It is not a full HL7 data type
+// library, just enough to catch obviously malformed values.
+func validDataType(value, dataType string) bool {
+	switch dataType {
+	case "NM":
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case "DT":
+		return isDigits(value) && (len(value) == 4 || len(value) == 6 || len(value) == 8)
+	case "TS":
+		return isDigits(value) && len(value) >= 8
+	case "ST", "ID", "IS", "FT", "TX":
+		return true
+	case "CE", "CWE", "XPN", "XAD", "XTN", "CX":
+		// Composite types: any populated value is acceptable here, since
+		// component-level rules are profile-specific.
+		return value != ""
+	default:
+		return true
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
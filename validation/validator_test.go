@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/sodrety/hl7-middleware/hl7"
+)
+
+func TestValidateMissingRequiredSegment(t *testing.T) {
+	profile := &Profile{
+		MessageType: "ADT^A01",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "MSH", Required: true},
+			{Name: "PID", Required: true},
+		},
+	}
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|SEND|FAC||||||MSG1|P|2.5\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	issues := Validate(msg, profile)
+	if len(issues) != 1 || issues[0].Code != "SEGMENT_MISSING" || issues[0].Segment != "PID" {
+		t.Fatalf("issues = %+v, want exactly one SEGMENT_MISSING for PID", issues)
+	}
+}
+
+func TestValidateRequiredFieldMissing(t *testing.T) {
+	profile := &Profile{
+		MessageType: "ADT^A01",
+		Version:     "2.5",
+		Segments: []SegmentDef{
+			{Name: "PID", Required: true, Fields: []FieldDef{
+				{Index: 5, Name: "Patient Name", Required: true},
+			}},
+		},
+	}
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|SEND|FAC||||||MSG1|P|2.5\rPID|||12345\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	issues := Validate(msg, profile)
+	if len(issues) != 1 || issues[0].Code != "FIELD_MISSING" || issues[0].FieldIndex != 5 {
+		t.Fatalf("issues = %+v, want exactly one FIELD_MISSING for PID-5", issues)
+	}
+}
+
+func TestValidateCleanMessageHasNoIssues(t *testing.T) {
+	profile, ok := BuiltinProfile("ADT^A01", "2.5")
+	if !ok {
+		t.Fatalf("expected a built-in ADT^A01 2.5 profile")
+	}
+	msg, err := hl7.ParseHL7Message("MSH|^~\\&|SEND|FAC|RECV|RECV_FAC|20240101120000||ADT^A01|MSG1|P|2.5\rEVN|A01|20240101120000\rPID|||12345||DOE^JOHN||19800101|M\rPV1||I\r")
+	if err != nil {
+		t.Fatalf("ParseHL7Message: %v", err)
+	}
+
+	if issues := Validate(msg, profile); len(issues) != 0 {
+		t.Errorf("issues = %+v, want none", issues)
+	}
+}